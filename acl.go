@@ -0,0 +1,167 @@
+package hypercat
+
+import (
+	"sort"
+	"sync"
+)
+
+// ACL is an access control list restricting which principals may see the
+// items of a catalogue. It is kept as a side table keyed by href rather than
+// within item metadata, so that access rules are never published along with
+// the items they protect.
+//
+// Items without an entry are visible to everyone, including anonymous
+// principals. Entries are independent of the catalogue, so an entry remains
+// in force if its item is removed and later added again. An ACL attached to a
+// catalogue as an Index moves its entries along with the items whose hrefs
+// are rewritten by Rebase or ResolveHrefs; otherwise those items would become
+// visible to everyone. It is safe for concurrent use.
+type ACL struct {
+	mu      sync.RWMutex
+	entries map[string]map[string]bool
+}
+
+// NewACL is a constructor function that creates and returns an empty ACL
+// instance.
+func NewACL() *ACL {
+	return &ACL{
+		entries: make(map[string]map[string]bool),
+	}
+}
+
+// Restrict limits the visibility of the item with the given href to the given
+// principals, replacing any existing entry. Restricting an item to no
+// principals hides it from everyone.
+func (a *ACL) Restrict(href string, principals ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	allowed := make(map[string]bool, len(principals))
+
+	for _, principal := range principals {
+		allowed[principal] = true
+	}
+
+	a.entries[href] = allowed
+}
+
+// Grant allows a principal to see the item with the given href. If the item
+// has no entry it becomes restricted to that principal.
+func (a *ACL) Grant(href, principal string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.entries[href] == nil {
+		a.entries[href] = make(map[string]bool)
+	}
+
+	a.entries[href][principal] = true
+}
+
+// Revoke prevents a principal from seeing the item with the given href. It has
+// no effect on items without an entry, which remain visible to everyone.
+func (a *ACL) Revoke(href, principal string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if allowed := a.entries[href]; allowed != nil {
+		delete(allowed, principal)
+	}
+}
+
+// Clear removes the entry for the item with the given href, making it visible
+// to everyone.
+func (a *ACL) Clear(href string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.entries, href)
+}
+
+// Principals returns the sorted principals allowed to see the item with the
+// given href, and whether the item has an entry at all.
+func (a *ACL) Principals(href string) ([]string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	allowed, ok := a.entries[href]
+	if !ok {
+		return nil, false
+	}
+
+	principals := make([]string, 0, len(allowed))
+
+	for principal := range allowed {
+		principals = append(principals, principal)
+	}
+
+	sort.Strings(principals)
+
+	return principals, true
+}
+
+// Visible reports whether the item with the given href is visible to the
+// principal.
+func (a *ACL) Visible(href, principal string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.visible(href, principal)
+}
+
+// visible reports whether the item with the given href is visible to the
+// principal. It must be called with the read lock held.
+func (a *ACL) visible(href, principal string) bool {
+	allowed, ok := a.entries[href]
+	if !ok {
+		return true
+	}
+
+	return allowed[principal]
+}
+
+// Reset implements Index. Entries are kept, as they are independent of the
+// items within the catalogue.
+func (a *ACL) Reset(items Items) {}
+
+// Apply implements Index. Entries are kept, as they are independent of the
+// items within the catalogue.
+func (a *ACL) Apply(ev Event) {}
+
+// rewriteHrefs moves the entries of items whose hrefs have been rewritten to
+// their new hrefs.
+func (a *ACL) rewriteHrefs(renamed map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	moved := make(map[string]map[string]bool, len(renamed))
+
+	for old, href := range renamed {
+		if allowed, ok := a.entries[old]; ok {
+			moved[href] = allowed
+			delete(a.entries, old)
+		}
+	}
+
+	for href, allowed := range moved {
+		a.entries[href] = allowed
+	}
+}
+
+// View returns a copy of the catalogue containing only the items visible to
+// the given principal. The copy shares no metadata storage with the original,
+// and has no Feed or Clock attached.
+func (a *ACL) View(cat *Hypercat, principal string) *Hypercat {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	items := make(Items, 0, len(cat.Items))
+
+	for i := range cat.Items {
+		if a.visible(cat.Items[i].Href, principal) {
+			items = append(items, *cat.Items[i].clone())
+		}
+	}
+
+	return cat.withItems(items)
+}
@@ -0,0 +1,217 @@
+package hypercat
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestACLView(t *testing.T) {
+	cat := testCatalogue(4)
+	acl := NewACL()
+
+	acl.Restrict("/1", "alice", "bob")
+	acl.Grant("/2", "bob")
+	acl.Restrict("/3")
+	acl.Revoke("/1", "bob")
+
+	var testcases = []struct {
+		principal string
+		expected  []string
+	}{
+		{"", []string{"/0"}},
+		{"alice", []string{"/0", "/1"}},
+		{"bob", []string{"/0", "/2"}},
+	}
+
+	for _, testcase := range testcases {
+		view := acl.View(cat, testcase.principal)
+
+		if got := hrefs(view.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("ACL view error for '%v', expected '%v', got '%v'", testcase.principal, testcase.expected, got)
+		}
+	}
+
+	if len(cat.Items) != 4 {
+		t.Errorf("ACL view should not modify the catalogue")
+	}
+
+	principals, ok := acl.Principals("/1")
+	if !ok || !reflect.DeepEqual(principals, []string{"alice"}) {
+		t.Errorf("ACL principals error, expected '[alice]', got '%v'", principals)
+	}
+
+	acl.Clear("/3")
+
+	if !acl.Visible("/3", "") {
+		t.Errorf("Cleared item should be visible to everyone")
+	}
+}
+
+func TestHandlerACL(t *testing.T) {
+	handler := NewHandler(testCatalogue(2))
+	handler.Authenticator = BearerTokens(map[string]string{"secret": "partner"})
+	handler.ACL = NewACL()
+	handler.ACL.Restrict("/1", "partner")
+
+	auth := map[string]string{"Authorization": "Bearer secret"}
+
+	var testcases = []struct {
+		headers  map[string]string
+		expected int
+	}{
+		{nil, 1},
+		{auth, 2},
+	}
+
+	for _, testcase := range testcases {
+		w := serve(handler, "GET", "/cat", "", testcase.headers)
+
+		cat, err := Parse(w.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cat.Items) != testcase.expected {
+			t.Errorf("Handler ACL error, expected '%v' items, got '%v'", testcase.expected, len(cat.Items))
+		}
+
+		etag, _ := cat.ETag()
+		if w.Header().Get("ETag") != etag || w.Header().Get("Vary") != "Authorization" {
+			t.Errorf("Handler should tag the view served, expected '%v', got '%v'", etag, w.Header().Get("ETag"))
+		}
+	}
+
+	w := serve(handler, "DELETE", "/cat?href=/1", "", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNotFound, w.Code)
+	}
+
+	etag, _ := handler.ACL.View(handler.cat, "partner").ETag()
+
+	w = serve(handler, "DELETE", "/cat?href=/1", "", map[string]string{"Authorization": "Bearer secret", "If-Match": etag})
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNoContent, w.Code)
+	}
+
+	handler.ACL.Restrict("/0", "partner")
+
+	for _, href := range []string{"/0", "/1"} {
+		body := `{"href":"` + href + `","item-metadata":[{"rel":"` + DescriptionRel + `","val":"Item"}]}`
+
+		w = serve(handler, "POST", "/cat", body, nil)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Handler status error for '%v', expected '%v', got '%v'", href, http.StatusForbidden, w.Code)
+		}
+	}
+}
+
+func TestACLFollowsRewrittenHrefs(t *testing.T) {
+	base, _ := url.Parse("http://example.com/catalogue")
+
+	handler := NewHandler(testCatalogue(2))
+	handler.cat.BaseURL = base
+	handler.Authenticator = BearerTokens(map[string]string{"secret": "partner"})
+	handler.ACL = NewACL()
+	handler.ACL.Restrict("/1", "partner")
+
+	other, _ := url.Parse("http://other.example.com/")
+
+	rewrites := []func(cat *Hypercat) error{
+		func(cat *Hypercat) error { return cat.ResolveHrefs() },
+		func(cat *Hypercat) error { return cat.Rebase(base) },
+		func(cat *Hypercat) error { return cat.Rebase(other) },
+	}
+
+	for i, rewrite := range rewrites {
+		err := handler.Update(rewrite)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var testcases = []struct {
+			headers  map[string]string
+			expected int
+		}{
+			{nil, 1},
+			{map[string]string{"Authorization": "Bearer secret"}, 2},
+		}
+
+		for _, testcase := range testcases {
+			cat, err := Parse(serve(handler, "GET", "/cat", "", testcase.headers).Body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(cat.Items) != testcase.expected {
+				t.Errorf("Handler ACL error after rewrite %v, expected '%v' items, got '%v'", i, testcase.expected, len(cat.Items))
+			}
+		}
+	}
+
+	if _, ok := handler.ACL.Principals("http://example.com/1"); !ok {
+		t.Errorf("ACL entry should follow the rewritten href, got '%v'", handler.cat.Items[1].Href)
+	}
+}
+
+func TestHandlerACLFeed(t *testing.T) {
+	handler := NewHandler(testCatalogue(2))
+	handler.Authenticator = BearerTokens(map[string]string{"secret": "partner"})
+	handler.ACL = NewACL()
+	handler.ACL.Restrict("/1", "partner")
+
+	handler.Update(func(cat *Hypercat) error {
+		cat.Feed = NewFeed(10)
+		cat.ReplaceItem(NewItem("/1", "Hidden item"))
+		cat.ReplaceItem(NewItem("/0", "Visible item"))
+
+		return nil
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.ServeFeed))
+	defer server.Close()
+
+	var testcases = []struct {
+		token    string
+		expected string
+	}{
+		{"", "id: 2"},
+		{"secret", "id: 1"},
+	}
+
+	for _, testcase := range testcases {
+		req, err := http.NewRequest("GET", server.URL+"?since=0", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if testcase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testcase.token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got, err := bufio.NewReader(resp.Body).ReadString('\n')
+		resp.Body.Close()
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if strings.TrimSpace(got) != testcase.expected {
+			t.Errorf("Feed ACL error, expected '%v', got '%v'", testcase.expected, strings.TrimSpace(got))
+		}
+	}
+
+	w := serve(http.HandlerFunc(NewHandler(testCatalogue(1)).ServeFeed), "GET", "/events", "", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNotFound, w.Code)
+	}
+}
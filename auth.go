@@ -0,0 +1,232 @@
+package hypercat
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Operation identifies the kind of access to a catalogue being authorized.
+type Operation int
+
+const (
+	// ReadOperation is the operation of retrieving or searching a catalogue.
+	ReadOperation Operation = iota
+
+	// AddOperation is the operation of adding an item, as with AddItem.
+	AddOperation
+
+	// ReplaceOperation is the operation of replacing an item, as with
+	// ReplaceItem.
+	ReplaceOperation
+
+	// DeleteOperation is the operation of removing an item, as with RemoveItem.
+	DeleteOperation
+)
+
+// String returns the name of the operation.
+func (op Operation) String() string {
+	switch op {
+	case ReadOperation:
+		return "read"
+	case AddOperation:
+		return "add"
+	case ReplaceOperation:
+		return "replace"
+	case DeleteOperation:
+		return "delete"
+	default:
+		return "Operation(" + strconv.Itoa(int(op)) + ")"
+	}
+}
+
+// Authenticator identifies the principal making an HTTP request. It returns
+// an empty principal and a nil error if the request carries no credentials it
+// recognises, and ErrInvalidCredentials if the credentials are not valid.
+type Authenticator interface {
+	Authenticate(r *http.Request) (principal string, err error)
+}
+
+// AuthenticatorFunc is an adapter allowing an ordinary function to be used as
+// an Authenticator, providing a hook for custom credential verifiers.
+type AuthenticatorFunc func(r *http.Request) (string, error)
+
+// Authenticate calls f(r).
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// Challenger is implemented by Authenticators that can describe the
+// credentials they accept, as the value of a WWW-Authenticate header.
+type Challenger interface {
+	Challenge() string
+}
+
+// apiKeyAuthenticator authenticates HTTP Basic credentials whose user name is
+// an API key.
+type apiKeyAuthenticator map[string]string
+
+// BasicAPIKeys returns an Authenticator accepting HTTP Basic credentials whose
+// user name is one of the given API keys, as described by the Hypercat
+// specification. The password is ignored. Keys are mapped to the principal
+// they authenticate.
+func BasicAPIKeys(keys map[string]string) Authenticator {
+	return apiKeyAuthenticator(keys)
+}
+
+// Authenticate implements Authenticator.
+func (a apiKeyAuthenticator) Authenticate(r *http.Request) (string, error) {
+	key, _, ok := r.BasicAuth()
+	if !ok {
+		return "", nil
+	}
+
+	principal, ok := a[key]
+	if !ok {
+		return "", ErrInvalidCredentials
+	}
+
+	return principal, nil
+}
+
+// Challenge implements Challenger.
+func (a apiKeyAuthenticator) Challenge() string {
+	return `Basic realm="hypercat"`
+}
+
+// bearerAuthenticator authenticates bearer tokens.
+type bearerAuthenticator map[string]string
+
+// BearerTokens returns an Authenticator accepting the given bearer tokens in
+// the Authorization header. Tokens are mapped to the principal they
+// authenticate.
+func BearerTokens(tokens map[string]string) Authenticator {
+	return bearerAuthenticator(tokens)
+}
+
+// Authenticate implements Authenticator.
+func (a bearerAuthenticator) Authenticate(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return "", nil
+	}
+
+	principal, ok := a[strings.TrimSpace(header[7:])]
+	if !ok {
+		return "", ErrInvalidCredentials
+	}
+
+	return principal, nil
+}
+
+// Challenge implements Challenger.
+func (a bearerAuthenticator) Challenge() string {
+	return `Bearer realm="hypercat"`
+}
+
+// multiAuthenticator tries several Authenticators in turn.
+type multiAuthenticator []Authenticator
+
+// AnyAuthenticator returns an Authenticator that tries each of the given
+// Authenticators in turn, returning the first principal identified or error
+// encountered.
+func AnyAuthenticator(auths ...Authenticator) Authenticator {
+	return multiAuthenticator(auths)
+}
+
+// Authenticate implements Authenticator.
+func (m multiAuthenticator) Authenticate(r *http.Request) (string, error) {
+	for _, auth := range m {
+		principal, err := auth.Authenticate(r)
+		if err != nil || principal != "" {
+			return principal, err
+		}
+	}
+
+	return "", nil
+}
+
+// Challenge implements Challenger.
+func (m multiAuthenticator) Challenge() string {
+	challenges := []string{}
+
+	for _, auth := range m {
+		if c, ok := auth.(Challenger); ok {
+			challenges = append(challenges, c.Challenge())
+		}
+	}
+
+	return strings.Join(challenges, ", ")
+}
+
+// Authorizer decides whether a principal may perform an operation on a
+// catalogue. The principal is empty for anonymous requests.
+type Authorizer func(principal string, op Operation) bool
+
+// PublicRead returns an Authorizer allowing anyone to read the catalogue, and
+// only the given principals to modify it. If no principals are given, any
+// authenticated principal may modify the catalogue.
+func PublicRead(writers ...string) Authorizer {
+	allowed := map[string]bool{}
+
+	for _, writer := range writers {
+		allowed[writer] = true
+	}
+
+	return func(principal string, op Operation) bool {
+		if op == ReadOperation {
+			return true
+		}
+
+		if len(allowed) == 0 {
+			return principal != ""
+		}
+
+		return allowed[principal]
+	}
+}
+
+// principalKey is the context key under which the authenticated principal is
+// stored.
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal authenticated by a Handler for
+// the request with the given context, or "" if the request is anonymous.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
+
+// CredentialProvider adds credentials to the requests made by a Client.
+type CredentialProvider interface {
+	Apply(req *http.Request) error
+}
+
+// CredentialFunc is an adapter allowing an ordinary function to be used as a
+// CredentialProvider.
+type CredentialFunc func(req *http.Request) error
+
+// Apply calls f(req).
+func (f CredentialFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAPIKey returns a CredentialProvider sending the given API key as the
+// user name of HTTP Basic credentials, matching BasicAPIKeys.
+func BasicAPIKey(key string) CredentialProvider {
+	return CredentialFunc(func(req *http.Request) error {
+		req.SetBasicAuth(key, "")
+		return nil
+	})
+}
+
+// BearerToken returns a CredentialProvider sending the given bearer token,
+// matching BearerTokens.
+func BearerToken(token string) CredentialProvider {
+	return CredentialFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package hypercat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const itemBody = `{"href":"/bar","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Bar"}]}`
+
+func TestHandlerAuth(t *testing.T) {
+	handler := testHandler(t)
+	handler.Authenticator = AnyAuthenticator(
+		BasicAPIKeys(map[string]string{"partner-key": "partner"}),
+		BearerTokens(map[string]string{"other-token": "other"}),
+	)
+	handler.Authorizer = PublicRead("partner")
+
+	var testcases = []struct {
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{"GET", nil, http.StatusOK},
+		{"POST", nil, http.StatusUnauthorized},
+		{"POST", map[string]string{"Authorization": "Basic d3Jvbmc6"}, http.StatusUnauthorized},
+		{"POST", map[string]string{"Authorization": "Bearer other-token"}, http.StatusForbidden},
+		{"POST", map[string]string{"Authorization": "Basic cGFydG5lci1rZXk6"}, http.StatusCreated},
+		{"DELETE", map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized},
+	}
+
+	for _, testcase := range testcases {
+		w := serve(handler, testcase.method, "/cat?href=/bar", itemBody, testcase.headers)
+
+		if w.Code != testcase.expected {
+			t.Errorf("Handler status error for '%v %v', expected '%v', got '%v'", testcase.method, testcase.headers, testcase.expected, w.Code)
+		}
+
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != `Basic realm="hypercat", Bearer realm="hypercat"` {
+			t.Errorf("Handler should challenge for credentials, got '%v'", w.Header().Get("WWW-Authenticate"))
+		}
+	}
+}
+
+func TestAuthenticatorFunc(t *testing.T) {
+	handler := NewHandler(NewHypercat("Catalogue description"))
+	handler.Authenticator = AuthenticatorFunc(func(r *http.Request) (string, error) {
+		return r.Header.Get("X-User"), nil
+	})
+	handler.Authorizer = func(principal string, op Operation) bool {
+		return op == ReadOperation || principal == "admin"
+	}
+
+	w := serve(handler, "POST", "/cat", itemBody, map[string]string{"X-User": "admin"})
+	if w.Code != http.StatusCreated {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusCreated, w.Code)
+	}
+
+	w = serve(handler, "POST", "/cat", itemBody, map[string]string{"X-User": "guest"})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusForbidden, w.Code)
+	}
+
+	w = serve(handler, "GET", "/cat", "", nil)
+	if w.Code != http.StatusOK || w.Header().Get("WWW-Authenticate") != "" {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusOK, w.Code)
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	handler := testHandler(t)
+	handler.Authenticator = BearerTokens(map[string]string{"secret": "partner"})
+	handler.Authorizer = PublicRead()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	item := NewItem("/bar", "Bar")
+	client := NewClient()
+
+	err := client.AddItem(context.Background(), server.URL, item)
+	if statusErr, ok := err.(*StatusError); !ok || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Client add error, expected status error, got '%v'", err)
+	}
+
+	client.Credentials = BearerToken("secret")
+
+	err = client.AddItem(context.Background(), server.URL, item)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	item.Description = "New"
+
+	err = client.ReplaceItem(context.Background(), server.URL, item)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = client.RemoveItem(context.Background(), server.URL, "/foo")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(handler.cat.Items) != 1 || handler.cat.Items[0].Description != "New" {
+		t.Errorf("Client writes not applied, got '%v'", handler.cat.Items)
+	}
+}
+
+func TestClientCredentialsCrossOriginPages(t *testing.T) {
+	auth := map[string]string{}
+
+	page := func(name, next string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth[name] = r.Header.Get("Authorization")
+
+			cat := NewHypercat(name)
+			if next != "" {
+				cat.AddRel(NextPageRel, next)
+			}
+
+			json.NewEncoder(w).Encode(cat)
+		})
+	}
+
+	other := httptest.NewServer(page("other", ""))
+	defer other.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/first", page("first", "/second"))
+	mux.Handle("/second", page("second", other.URL))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient()
+	client.Credentials = BearerToken("secret")
+
+	_, err := client.FetchAll(context.Background(), server.URL+"/first")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"first": "Bearer secret", "second": "Bearer secret", "other": ""}
+
+	if !reflect.DeepEqual(auth, expected) {
+		t.Errorf("Client credentials error, expected '%v', got '%v'", expected, auth)
+	}
+}
@@ -0,0 +1,125 @@
+package hypercat
+
+import "net/url"
+
+// WithBaseURL returns a ParseOption that sets the BaseURL of the parsed
+// catalogue, against which relative item hrefs are resolved. This is normally
+// the URL the document was retrieved from.
+func WithBaseURL(base *url.URL) ParseOption {
+	return func(o *parseOptions) {
+		o.baseURL = base
+	}
+}
+
+// ResolveHref returns the absolute URL identified by the given href, resolved
+// against the catalogue's BaseURL. If the catalogue has no BaseURL the parsed
+// href is returned as is, and may therefore be relative.
+func (h *Hypercat) ResolveHref(href string) (*url.URL, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.BaseURL == nil {
+		return u, nil
+	}
+
+	return h.BaseURL.ResolveReference(u), nil
+}
+
+// ResolveHrefs rewrites the href of every item within the catalogue as an
+// absolute URL resolved against the catalogue's BaseURL. Returns
+// ErrMissingBaseURL if the catalogue has no BaseURL, in which case no hrefs
+// are changed.
+//
+// Like Rebase, this changes how hrefs are written rather than the resources
+// they identify, so no events are published and no timestamps are updated.
+// Any attached indexes are reset.
+func (h *Hypercat) ResolveHrefs() error {
+	return h.rewriteHrefs(func(u *url.URL) string {
+		return u.String()
+	})
+}
+
+// Rebase moves the catalogue to a new base URL, rewriting the href of every
+// item so that it continues to identify the same resource. Hrefs sharing the
+// scheme and host of the new base are written as absolute paths, and all
+// others as absolute URLs. Returns ErrMissingBaseURL if the catalogue has no
+// BaseURL or base is nil, in which case the catalogue is left unchanged.
+//
+// Rebase doesn't publish events or update timestamps, as the items themselves
+// are unchanged. Any attached indexes are reset.
+func (h *Hypercat) Rebase(base *url.URL) error {
+	if base == nil {
+		return ErrMissingBaseURL
+	}
+
+	err := h.rewriteHrefs(func(u *url.URL) string {
+		if u.Scheme != base.Scheme || u.Host != base.Host || u.User != nil {
+			return u.String()
+		}
+
+		path := *u
+		path.Scheme = ""
+		path.Host = ""
+
+		return path.String()
+	})
+	if err != nil {
+		return err
+	}
+
+	h.BaseURL = copyURL(base)
+
+	return nil
+}
+
+// rewriteHrefs replaces the href of every item with the result of calling fn
+// with its absolute URL. No hrefs are changed if any of them fails to parse.
+func (h *Hypercat) rewriteHrefs(fn func(u *url.URL) string) error {
+	if h.BaseURL == nil {
+		return ErrMissingBaseURL
+	}
+
+	hrefs := make([]string, len(h.Items))
+
+	for i := range h.Items {
+		u, err := h.ResolveHref(h.Items[i].Href)
+		if err != nil {
+			return err
+		}
+
+		hrefs[i] = fn(u)
+	}
+
+	renamed := make(map[string]string, len(h.Items))
+
+	for i := range h.Items {
+		if hrefs[i] != h.Items[i].Href {
+			renamed[h.Items[i].Href] = hrefs[i]
+		}
+
+		h.Items[i].Href = hrefs[i]
+	}
+
+	for _, idx := range h.Indexes {
+		if rw, ok := idx.(hrefRewriter); ok {
+			rw.rewriteHrefs(renamed)
+		}
+	}
+
+	h.resetIndexes()
+
+	return nil
+}
+
+// copyURL returns a copy of the given URL, or nil if it is nil.
+func copyURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+
+	c := *u
+
+	return &c
+}
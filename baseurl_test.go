@@ -0,0 +1,98 @@
+package hypercat
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveHref(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+
+	u, err := cat.ResolveHref("/resource1")
+	if err != nil || u.String() != "/resource1" {
+		t.Errorf("ResolveHref error, expected '/resource1', got '%v' (%v)", u, err)
+	}
+
+	cat.BaseURL, _ = url.Parse("http://example.com/cats/main")
+
+	var testcases = []struct {
+		href     string
+		expected string
+	}{
+		{"/resource1", "http://example.com/resource1"},
+		{"resource2?x=1", "http://example.com/cats/resource2?x=1"},
+		{"../other", "http://example.com/other"},
+		{"https://example.org/resource3", "https://example.org/resource3"},
+	}
+
+	for _, testcase := range testcases {
+		u, err := cat.ResolveHref(testcase.href)
+		if err != nil || u.String() != testcase.expected {
+			t.Errorf("ResolveHref error, expected '%v', got '%v' (%v)", testcase.expected, u, err)
+		}
+	}
+}
+
+func TestParseBaseURL(t *testing.T) {
+	base, _ := url.Parse("http://example.com/cat")
+
+	cat, err := Parse(strings.NewReader(testDocument(`{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Foo"}]}`)), WithBaseURL(base))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cat.BaseURL.String() != base.String() || cat.BaseURL == base {
+		t.Errorf("Parse base URL error, expected a copy of '%v', got '%v'", base, cat.BaseURL)
+	}
+
+	page, _ := cat.Paginate(0, 1)
+
+	if page.BaseURL.String() != base.String() {
+		t.Errorf("Paginate base URL error, expected '%v', got '%v'", base, page.BaseURL)
+	}
+}
+
+func TestRebase(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+
+	for _, href := range []string{"/resource1", "resource2", "http://other.com/resource3", "http://example.com/resource4#top"} {
+		cat.AddItem(NewItem(href, "description"))
+	}
+
+	newBase, _ := url.Parse("http://example.com/mirror/cat")
+
+	err := cat.Rebase(newBase)
+	if err != ErrMissingBaseURL {
+		t.Errorf("Rebase error, expected '%v', got '%v'", ErrMissingBaseURL, err)
+	}
+
+	cat.BaseURL, _ = url.Parse("http://example.com/cats/main")
+
+	err = cat.Rebase(newBase)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"/resource1", "/cats/resource2", "http://other.com/resource3", "/resource4#top"}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Rebase error, expected '%v', got '%v'", expected, got)
+	}
+
+	if cat.BaseURL.String() != newBase.String() {
+		t.Errorf("Rebase error, expected base URL '%v', got '%v'", newBase, cat.BaseURL)
+	}
+
+	err = cat.ResolveHrefs()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = []string{"http://example.com/resource1", "http://example.com/cats/resource2", "http://other.com/resource3", "http://example.com/resource4#top"}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ResolveHrefs error, expected '%v', got '%v'", expected, got)
+	}
+}
@@ -0,0 +1,207 @@
+package hypercat
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Builder provides a fluent API for constructing catalogues. Each method
+// returns the Builder so that calls can be chained, and any errors are
+// accumulated and reported together by Build:
+//
+//	cat, err := hypercat.NewBuilder("Sensors").
+//		Rel(hypercat.SupportsSearchRel, hypercat.PrefixSearchVal).
+//		Item("/sensors/1", "Temperature sensor").
+//		Location(51.5, -0.12).
+//		Int("urn:example:interval", 60).
+//		SubCatalogue("/parks", "Parks catalogue").
+//		Build()
+//
+// Rels and values are added to the item most recently started by Item or
+// SubCatalogue, or to the catalogue before any item has been started.
+// CatalogueRel always adds to the catalogue. A Builder must not be used after
+// Build has been called.
+type Builder struct {
+	cat  *Hypercat
+	item *Item
+	errs BuildError
+}
+
+// NewBuilder is a constructor function that creates and returns a Builder for
+// a catalogue with the given description.
+func NewBuilder(description string) *Builder {
+	return &Builder{
+		cat: NewHypercat(description),
+	}
+}
+
+// Item starts a new item with the given href and description, to which
+// subsequent rels are added.
+func (b *Builder) Item(href, description string) *Builder {
+	b.flush()
+
+	b.item = NewItem(href, description)
+
+	if href == "" {
+		b.fail(ErrMissingHref)
+	}
+
+	if description == "" {
+		b.fail(ErrMissingDescriptionRel)
+	}
+
+	return b
+}
+
+// SubCatalogue starts a new item linking to another catalogue with the given
+// href and description. The item's content type is set to HypercatMediaType.
+func (b *Builder) SubCatalogue(href, description string) *Builder {
+	b.Item(href, description)
+	b.item.AddRel(ContentTypeRel, HypercatMediaType)
+
+	return b
+}
+
+// Rel adds a Rel to the current item or catalogue.
+func (b *Builder) Rel(rel, val string) *Builder {
+	b.target().Add(rel, val)
+
+	return b
+}
+
+// CatalogueRel adds a Rel to the catalogue, regardless of whether an item has
+// been started.
+func (b *Builder) CatalogueRel(rel, val string) *Builder {
+	b.cat.Metadata.Add(rel, val)
+
+	return b
+}
+
+// DescriptionIn sets the description of the current item or catalogue in the
+// given language.
+func (b *Builder) DescriptionIn(lang, text string) *Builder {
+	if b.item != nil {
+		b.item.SetDescriptionIn(lang, text)
+	} else {
+		b.cat.SetDescriptionIn(lang, text)
+	}
+
+	return b
+}
+
+// Float sets a rel of the current item or catalogue to a float64, replacing
+// any existing values.
+func (b *Builder) Float(rel string, f float64) *Builder {
+	return b.setTyped(rel, formatFloat(f), FloatValue)
+}
+
+// Int sets a rel of the current item or catalogue to an int64, replacing any
+// existing values.
+func (b *Builder) Int(rel string, i int64) *Builder {
+	return b.setTyped(rel, formatInt(i), IntValue)
+}
+
+// Bool sets a rel of the current item or catalogue to a bool, replacing any
+// existing values.
+func (b *Builder) Bool(rel string, v bool) *Builder {
+	return b.setTyped(rel, strconv.FormatBool(v), BoolValue)
+}
+
+// Time sets a rel of the current item or catalogue to an ISO 8601 timestamp,
+// replacing any existing values.
+func (b *Builder) Time(rel string, t time.Time) *Builder {
+	return b.setTyped(rel, formatTime(t), TimeValue)
+}
+
+// URL sets a rel of the current item or catalogue to the given URL, replacing
+// any existing values. An error is recorded if the URL can't be parsed.
+func (b *Builder) URL(rel, rawurl string) *Builder {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		b.fail(relError(rel, rawurl, err))
+		return b
+	}
+
+	return b.setTyped(rel, u.String(), URLValue)
+}
+
+// Location sets the WGS84 latitude and longitude of the current item or
+// catalogue. An error is recorded if either coordinate is out of range, NaN
+// or infinite.
+func (b *Builder) Location(lat, lng float64) *Builder {
+	if !validCoordinate(lat, 90) {
+		b.fail(&RelError{Rel: LatitudeRel, Val: formatFloat(lat), Err: ErrValueNotAllowed})
+		return b
+	}
+
+	if !validCoordinate(lng, 180) {
+		b.fail(&RelError{Rel: LongitudeRel, Val: formatFloat(lng), Err: ErrValueNotAllowed})
+		return b
+	}
+
+	b.Float(LatitudeRel, lat)
+	b.Float(LongitudeRel, lng)
+
+	return b
+}
+
+// Build adds the last item started to the catalogue and returns it. If any
+// errors were encountered while building, the catalogue is discarded and a
+// BuildError listing all of them is returned instead.
+func (b *Builder) Build() (*Hypercat, error) {
+	b.flush()
+
+	if len(b.errs) > 0 {
+		return nil, b.errs
+	}
+
+	return b.cat, nil
+}
+
+// target returns the metadata that rels are currently added to.
+func (b *Builder) target() *Metadata {
+	if b.item != nil {
+		return &b.item.Metadata
+	}
+
+	return &b.cat.Metadata
+}
+
+// setTyped sets a rel of the current item or catalogue to a formatted value,
+// recording an error if the rel is registered with a different type.
+func (b *Builder) setTyped(rel, val string, t ValueType) *Builder {
+	err := checkRegistered(rel, val, t)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+
+	b.target().Set(rel, val)
+
+	return b
+}
+
+// fail records an error, identifying the current item if there is one.
+func (b *Builder) fail(err error) {
+	if b.item != nil {
+		err = fmt.Errorf("item %q: %w", b.item.Href, err)
+	}
+
+	b.errs = append(b.errs, err)
+}
+
+// flush adds the current item, if any, to the catalogue.
+func (b *Builder) flush() {
+	if b.item == nil {
+		return
+	}
+
+	err := b.cat.AddItem(b.item)
+	if err != nil {
+		b.fail(err)
+	}
+
+	b.item = nil
+}
@@ -0,0 +1,102 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	updated := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cat, err := NewBuilder("Catalogue description").
+		Rel(SupportsSearchRel, PrefixSearchVal).
+		Item("/sensors/1", "Temperature sensor").
+		Location(51.5, -0.125).
+		Int("urn:example:interval", 60).
+		Time(LastUpdatedRel, updated).
+		DescriptionIn("fr", "Capteur de température").
+		SubCatalogue("/parks", "Parks").
+		URL(HomepageRel, "http://example.com/parks").
+		CatalogueRel("relation", "value").
+		Build()
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := NewHypercat("Catalogue description")
+	expected.AddRel(SupportsSearchRel, PrefixSearchVal)
+
+	sensor := NewItem("/sensors/1", "Temperature sensor")
+	sensor.SetFloat(LatitudeRel, 51.5)
+	sensor.SetFloat(LongitudeRel, -0.125)
+	sensor.SetInt("urn:example:interval", 60)
+	sensor.SetTime(LastUpdatedRel, updated)
+	sensor.SetDescriptionIn("fr", "Capteur de température")
+	expected.AddItem(sensor)
+
+	parks := NewItem("/parks", "Parks")
+	parks.AddRel(ContentTypeRel, HypercatMediaType)
+	parks.AddRel(HomepageRel, "http://example.com/parks")
+	expected.AddItem(parks)
+
+	expected.AddRel("relation", "value")
+
+	got, _ := json.Marshal(cat)
+	want, _ := json.Marshal(expected)
+
+	if string(got) != string(want) {
+		t.Errorf("Builder error, expected '%v', got '%v'", string(want), string(got))
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	cat, err := NewBuilder("Catalogue description").
+		Item("/foo", "Foo").
+		Location(91, 0).
+		Item("/foo", "Foo again").
+		Float(HomepageRel, 1).
+		Item("", "Anonymous").
+		Build()
+
+	if cat != nil {
+		t.Errorf("Builder should not return a catalogue when reporting errors")
+	}
+
+	buildErr, ok := err.(BuildError)
+	if !ok || len(buildErr) != 4 {
+		t.Fatalf("Builder error, expected 4 errors, got '%v'", err)
+	}
+
+	for _, target := range []error{ErrValueNotAllowed, ErrDuplicateHref, ErrTypeMismatch, ErrMissingHref} {
+		if !errors.Is(err, target) {
+			t.Errorf("Builder error, expected '%v' within '%v'", target, err)
+		}
+	}
+}
+
+func TestBuilderLocationNotFinite(t *testing.T) {
+	var testcases = []struct {
+		lat float64
+		lng float64
+	}{
+		{math.NaN(), 0},
+		{0, math.NaN()},
+		{math.Inf(1), 0},
+		{0, math.Inf(-1)},
+	}
+
+	for _, testcase := range testcases {
+		_, err := NewBuilder("Catalogue description").
+			Item("/foo", "Foo").
+			Location(testcase.lat, testcase.lng).
+			Build()
+
+		if !errors.Is(err, ErrValueNotAllowed) {
+			t.Errorf("Builder Location error for '%v, %v', expected '%v', got '%v'", testcase.lat, testcase.lng, ErrValueNotAllowed, err)
+		}
+	}
+}
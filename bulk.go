@@ -0,0 +1,65 @@
+package hypercat
+
+import "fmt"
+
+// AddItems is a function for adding several Items to a catalogue at once. The
+// operation is transactional: if any item has an href that is already defined
+// within the catalogue, or that is repeated within items, an error wrapping
+// ErrDuplicateHref is returned and the catalogue is left unchanged.
+func (h *Hypercat) AddItems(items Items) error {
+	hrefs := make(map[string]bool, len(h.Items)+len(items))
+
+	for i := range h.Items {
+		hrefs[h.Items[i].Href] = true
+	}
+
+	for i := range items {
+		if hrefs[items[i].Href] {
+			return fmt.Errorf("%w: %q", ErrDuplicateHref, items[i].Href)
+		}
+
+		hrefs[items[i].Href] = true
+	}
+
+	for i := range items {
+		h.storeItem(-1, &items[i])
+	}
+
+	return nil
+}
+
+// RemoveItemsWhere is a function for removing every item from a catalogue for
+// which the given predicate returns true. Returns the number of items removed.
+// The predicate must not modify the catalogue.
+func (h *Hypercat) RemoveItemsWhere(pred func(item *Item) bool) int {
+	removed := []string{}
+	kept := h.Items[:0]
+
+	for i := range h.Items {
+		if pred(&h.Items[i]) {
+			removed = append(removed, h.Items[i].Href)
+			continue
+		}
+
+		kept = append(kept, h.Items[i])
+	}
+
+	// Clear the tail of the backing array so removed items can be collected.
+	for i := len(kept); i < len(h.Items); i++ {
+		h.Items[i] = Item{}
+	}
+
+	h.Items = kept
+
+	if len(removed) == 0 {
+		return 0
+	}
+
+	h.touch(nil)
+
+	for _, href := range removed {
+		h.publish(Event{Type: ItemRemoved, Href: href})
+	}
+
+	return len(removed)
+}
@@ -0,0 +1,73 @@
+package hypercat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAddItems(t *testing.T) {
+	cat := testCatalogue(2)
+	cat.Feed = NewFeed(0)
+
+	err := cat.AddItems(Items{*NewItem("/2", "Item 2"), *NewItem("/3", "Item 3")})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, []string{"/0", "/1", "/2", "/3"}) {
+		t.Errorf("AddItems error, expected '[/0 /1 /2 /3]', got '%v'", got)
+	}
+
+	if cat.Feed.Seq() != 2 {
+		t.Errorf("AddItems should publish an event per item, got '%v'", cat.Feed.Seq())
+	}
+
+	var testcases = []Items{
+		{*NewItem("/4", "Item 4"), *NewItem("/1", "Item 1")},
+		{*NewItem("/4", "Item 4"), *NewItem("/4", "Item 4")},
+	}
+
+	for _, items := range testcases {
+		err = cat.AddItems(items)
+		if !errors.Is(err, ErrDuplicateHref) {
+			t.Errorf("AddItems error, expected '%v', got '%v'", ErrDuplicateHref, err)
+		}
+
+		if len(cat.Items) != 4 || cat.Feed.Seq() != 2 {
+			t.Errorf("A failed AddItems should leave the catalogue unchanged, got '%v'", hrefs(cat.Items))
+		}
+	}
+}
+
+func TestRemoveItemsWhere(t *testing.T) {
+	cat := testCatalogue(5)
+	cat.Feed = NewFeed(0)
+
+	removed := cat.RemoveItemsWhere(func(item *Item) bool {
+		return item.Href == "/1" || item.Href == "/3"
+	})
+
+	if removed != 2 {
+		t.Errorf("RemoveItemsWhere error, expected '2', got '%v'", removed)
+	}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, []string{"/0", "/2", "/4"}) {
+		t.Errorf("RemoveItemsWhere error, expected '[/0 /2 /4]', got '%v'", got)
+	}
+
+	events, err := cat.Feed.Since(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Type != ItemRemoved || events[1].Href != "/3" {
+		t.Errorf("RemoveItemsWhere should publish an event per removed item, got '%v'", events)
+	}
+
+	removed = cat.RemoveItemsWhere(func(item *Item) bool { return false })
+
+	if removed != 0 || len(cat.Items) != 3 {
+		t.Errorf("RemoveItemsWhere error, expected no items removed, got '%v'", removed)
+	}
+}
@@ -0,0 +1,82 @@
+package hypercat
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// CanonicalJSON returns the canonical JSON encoding of the catalogue, which is
+// identical for any two catalogues holding the same items and metadata
+// regardless of the order in which they were added. This makes it suitable for
+// content hashing, comparison and signing.
+//
+// In the canonical encoding items are sorted by href, and the Rels of the
+// catalogue and of each item (including those stored in the Description and
+// ContentType fields) are sorted by rel and then by value. No insignificant
+// whitespace is emitted, and strings are escaped using only the escapes
+// required by JSON, so characters such as '<', '>' and '&' appear literally.
+// Unknown fields held in Extra follow the standard fields, sorted by key, with
+// the keys of any objects they contain sorted too.
+func (h *Hypercat) CanonicalJSON() ([]byte, error) {
+	items := make(Items, len(h.Items))
+	copy(items, h.Items)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Href < items[j].Href
+	})
+
+	encoded := make([]json.RawMessage, len(items))
+
+	for i := range items {
+		b, err := items[i].CanonicalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		encoded[i] = b
+	}
+
+	return marshalObject([]jsonField{
+		{key: "items", value: encoded},
+		{key: "catalogue-metadata", value: sortedMetadata(h.allMetadata())},
+	}, h.Extra, true)
+}
+
+// CanonicalJSON returns the canonical JSON encoding of the item. See
+// Hypercat.CanonicalJSON for details of the encoding.
+func (item *Item) CanonicalJSON() ([]byte, error) {
+	return marshalObject([]jsonField{
+		{key: "href", value: item.Href},
+		{key: "item-metadata", value: sortedMetadata(item.allMetadata())},
+	}, item.Extra, true)
+}
+
+// sortedMetadata sorts the given metadata by rel and then by value.
+func sortedMetadata(metadata Metadata) Metadata {
+	sort.SliceStable(metadata, func(i, j int) bool {
+		if metadata[i].Rel != metadata[j].Rel {
+			return metadata[i].Rel < metadata[j].Rel
+		}
+
+		return metadata[i].Val < metadata[j].Val
+	})
+
+	return metadata
+}
+
+// canonicalEncode encodes the given value as JSON without HTML escaping or a
+// trailing newline.
+func canonicalEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
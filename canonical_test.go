@@ -0,0 +1,73 @@
+package hypercat
+
+import (
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	item1 := NewItem("/b", "Item <b>")
+	item1.AddRel("foo", "2")
+	item1.AddRel("bar", "1")
+	item1.AddRel("foo", "1")
+
+	item2 := NewItem("/a", "Item a")
+
+	cat1 := NewHypercat("Catalogue & co")
+	cat1.AddRel("foo", "bar")
+	cat1.AddItem(item1)
+	cat1.AddItem(item2)
+
+	item3 := NewItem("/b", "Item <b>")
+	item3.AddRel("foo", "1")
+	item3.AddRel("foo", "2")
+	item3.AddRel("bar", "1")
+
+	cat2 := NewHypercat("Catalogue & co")
+	cat2.AddItem(item2)
+	cat2.AddItem(item3)
+	cat2.AddRel("foo", "bar")
+
+	expected := `{"items":[` +
+		`{"href":"/a","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item a"}]},` +
+		`{"href":"/b","item-metadata":[{"rel":"bar","val":"1"},{"rel":"foo","val":"1"},{"rel":"foo","val":"2"},{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item <b>"}]}` +
+		`],"catalogue-metadata":[{"rel":"foo","val":"bar"},{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue & co"},{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}]}`
+
+	for _, cat := range []*Hypercat{cat1, cat2} {
+		bytes, err := cat.CanonicalJSON()
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if string(bytes) != expected {
+			t.Errorf("Canonical encoding error, expected '%v', got '%v'", expected, string(bytes))
+		}
+	}
+
+	if cat1.Items[1].Href != "/a" || cat1.Items[0].Metadata[0].Rel != "foo" {
+		t.Errorf("Canonical encoding should not reorder the catalogue")
+	}
+
+	etag1, _ := cat1.ETag()
+	etag2, _ := cat2.ETag()
+
+	if etag1 != etag2 {
+		t.Errorf("ETag should be identical for equivalent catalogues, got '%v' and '%v'", etag1, etag2)
+	}
+}
+
+func TestItemCanonicalJSON(t *testing.T) {
+	item := NewItem("/a", "Item a")
+	item.AddRel("foo", "<1>")
+	item.AddRel("bar", "1")
+
+	bytes, err := item.CanonicalJSON()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := `{"href":"/a","item-metadata":[{"rel":"bar","val":"1"},{"rel":"foo","val":"<1>"},{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item a"}]}`
+
+	if string(bytes) != expected {
+		t.Errorf("Canonical encoding error, expected '%v', got '%v'", expected, string(bytes))
+	}
+}
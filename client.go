@@ -0,0 +1,291 @@
+package hypercat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is an HTTP client for retrieving and modifying Hypercat catalogues.
+type Client struct {
+	HTTPClient  *http.Client
+	Credentials CredentialProvider // Optional credentials added to every request.
+}
+
+// NewClient is a constructor function that creates and returns a Client
+// instance using http.DefaultClient.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Fetch retrieves and parses the catalogue at the given URL. If the catalogue
+// is paginated only the requested page is returned; use Pages or FetchAll to
+// retrieve every page. The BaseURL of the returned catalogue is set to the URL
+// it was retrieved from, after following any redirects.
+func (c *Client) Fetch(ctx context.Context, rawurl string) (*Hypercat, error) {
+	return c.fetch(ctx, nil, rawurl)
+}
+
+// fetch retrieves and parses the catalogue at the given URL, as a page of the
+// catalogue first requested from origin. See newRequestFrom for details.
+func (c *Client) fetch(ctx context.Context, origin *url.URL, rawurl string) (*Hypercat, error) {
+	req, err := c.newRequestFrom(ctx, origin, "GET", rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parseResponse(req, http.StatusOK)
+}
+
+// parseResponse sends the request, checks for the expected response status
+// and parses the catalogue in the response body. The BaseURL of the catalogue
+// is set to the URL of the request, after following any redirects.
+func (c *Client) parseResponse(req *http.Request, status int) (*Hypercat, error) {
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		return nil, &StatusError{URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+
+	base := req.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+
+	return Parse(resp.Body, WithBaseURL(base))
+}
+
+// Pages calls fn with each page of the catalogue at the given URL in turn,
+// following the NextPageRel of each page until the last page has been
+// processed or fn returns an error. Relative page links are resolved against
+// the URL of the page containing them. Credentials are only sent with pages
+// sharing the scheme and host of the given URL, as page links are read from
+// the untrusted catalogue.
+func (c *Client) Pages(ctx context.Context, rawurl string, fn func(page *Hypercat) error) error {
+	origin, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	return c.pages(ctx, origin, rawurl, fn)
+}
+
+// pages implements Pages for the catalogue first requested from origin,
+// starting at the page with the given URL.
+func (c *Client) pages(ctx context.Context, origin *url.URL, rawurl string, fn func(page *Hypercat) error) error {
+	seen := map[string]bool{}
+
+	for rawurl != "" {
+		if seen[rawurl] {
+			return ErrPaginationLoop
+		}
+
+		seen[rawurl] = true
+
+		page, err := c.fetch(ctx, origin, rawurl)
+		if err != nil {
+			return err
+		}
+
+		next, err := nextPage(page)
+		if err != nil {
+			return err
+		}
+
+		err = fn(page)
+		if err != nil {
+			return err
+		}
+
+		rawurl = next
+	}
+
+	return nil
+}
+
+// FetchAll retrieves every page of the catalogue at the given URL, returning
+// a single catalogue containing the metadata of the first page and the items
+// of all pages. Items from pages at a different location to the first page
+// are rebased so that their hrefs resolve against the BaseURL of the result.
+func (c *Client) FetchAll(ctx context.Context, rawurl string) (*Hypercat, error) {
+	var cat *Hypercat
+
+	err := c.Pages(ctx, rawurl, func(page *Hypercat) error {
+		if cat == nil {
+			cat = page
+			return nil
+		}
+
+		return appendPage(cat, page)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cat.Metadata.Remove(NextPageRel)
+
+	return cat, nil
+}
+
+// appendPage appends the items of a page to a catalogue, rebasing them first
+// if the page is at a different location to the catalogue.
+func appendPage(cat, page *Hypercat) error {
+	if page.BaseURL.String() != cat.BaseURL.String() {
+		err := page.Rebase(cat.BaseURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	cat.Items = append(cat.Items, page.Items...)
+
+	return nil
+}
+
+// AddItem adds an item to the catalogue at the given URL by POSTing it, as
+// served by Handler.
+func (c *Client) AddItem(ctx context.Context, rawurl string, item *Item) error {
+	return c.write(ctx, "POST", rawurl, item, http.StatusCreated)
+}
+
+// ReplaceItem replaces the item with the same href within the catalogue at the
+// given URL by PUTting it, as served by Handler.
+func (c *Client) ReplaceItem(ctx context.Context, rawurl string, item *Item) error {
+	target, err := withHref(rawurl, item.Href)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, "PUT", target, item, http.StatusOK)
+}
+
+// RemoveItem removes the item with the given href from the catalogue at the
+// given URL with a DELETE request, as served by Handler.
+func (c *Client) RemoveItem(ctx context.Context, rawurl, href string) error {
+	target, err := withHref(rawurl, href)
+	if err != nil {
+		return err
+	}
+
+	return c.write(ctx, "DELETE", target, nil, http.StatusNoContent)
+}
+
+// write sends a request modifying a catalogue, with the JSON encoding of item
+// as its body if it isn't nil, and checks for the expected response status.
+func (c *Client) write(ctx context.Context, method, rawurl string, item *Item, status int) error {
+	var body io.Reader
+
+	if item != nil {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(b)
+	}
+
+	req, err := c.newRequest(ctx, method, rawurl, body)
+	if err != nil {
+		return err
+	}
+
+	if item != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		return &StatusError{URL: rawurl, StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// newRequest returns a request for the given URL accepting Hypercat
+// responses, with any configured credentials applied.
+func (c *Client) newRequest(ctx context.Context, method, rawurl string, body io.Reader) (*http.Request, error) {
+	return c.newRequestFrom(ctx, nil, method, rawurl, body)
+}
+
+// newRequestFrom returns a request for the given URL like newRequest, on
+// behalf of a caller that first requested origin. Credentials are only
+// applied if origin is nil or shares the URL's scheme and host, so that they
+// aren't disclosed to other hosts named by links within a catalogue, as
+// net/http does when following redirects.
+func (c *Client) newRequestFrom(ctx context.Context, origin *url.URL, method, rawurl string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawurl, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", HypercatMediaType+", application/json")
+
+	if c.Credentials != nil && (origin == nil || sameOrigin(req.URL, origin)) {
+		err = c.Credentials.Apply(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// sameOrigin reports whether two URLs share the same scheme and host.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// withHref returns the given URL with its "href" query parameter set.
+func withHref(rawurl, href string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("href", href)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// httpClient returns the configured HTTP client, or http.DefaultClient if
+// none is set.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return c.HTTPClient
+}
+
+// nextPage returns the absolute URL of the page following the given page, or
+// "" if it is the last page.
+func nextPage(page *Hypercat) (string, error) {
+	vals := page.Vals(NextPageRel)
+	if len(vals) == 0 {
+		return "", nil
+	}
+
+	next, err := page.ResolveHref(vals[0])
+	if err != nil {
+		return "", err
+	}
+
+	return next.String(), nil
+}
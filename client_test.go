@@ -0,0 +1,94 @@
+package hypercat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientFetch(t *testing.T) {
+	server := httptest.NewServer(NewHandler(testCatalogue(3)))
+	defer server.Close()
+
+	cat, err := NewClient().Fetch(context.Background(), server.URL+"/cat?limit=2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cat.Items) != 2 {
+		t.Errorf("Client fetch error, expected 2 items, got '%v'", len(cat.Items))
+	}
+
+	if cat.Vals(NextPageRel)[0] != "/cat?limit=2&offset=2" {
+		t.Errorf("Client fetch error, unexpected next page '%v'", cat.Vals(NextPageRel))
+	}
+
+	if cat.BaseURL.String() != server.URL+"/cat?limit=2" {
+		t.Errorf("Client fetch error, expected base URL '%v', got '%v'", server.URL+"/cat?limit=2", cat.BaseURL)
+	}
+}
+
+func TestClientFetchStatusError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewClient().Fetch(context.Background(), server.URL)
+
+	statusErr, ok := err.(*StatusError)
+	if !ok || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Client fetch error, expected status error, got '%v'", err)
+	}
+}
+
+func TestClientFetchAll(t *testing.T) {
+	server := httptest.NewServer(NewHandler(testCatalogue(5)))
+	defer server.Close()
+
+	for _, query := range []string{"?limit=2", "?limit=2&cursor="} {
+		pages := 0
+
+		err := NewClient().Pages(context.Background(), server.URL+query, func(page *Hypercat) error {
+			pages++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if pages != 3 {
+			t.Errorf("Client pages error, expected 3 pages, got '%v'", pages)
+		}
+
+		cat, err := NewClient().FetchAll(context.Background(), server.URL+query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cat.Items) != 5 {
+			t.Errorf("Client fetch all error, expected 5 items, got '%v'", len(cat.Items))
+		}
+
+		if !reflect.DeepEqual(hrefs(cat.Items), []string{"/0", "/1", "/2", "/3", "/4"}) {
+			t.Errorf("Client fetch all error, unexpected items '%v'", hrefs(cat.Items))
+		}
+
+		if len(cat.Vals(NextPageRel)) != 0 {
+			t.Errorf("Client fetch all should remove next page links")
+		}
+	}
+}
+
+func TestClientPaginationLoop(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+	cat.AddRel(NextPageRel, "/cat")
+
+	server := httptest.NewServer(NewHandler(cat))
+	defer server.Close()
+
+	_, err := NewClient().FetchAll(context.Background(), server.URL+"/cat")
+	if err != ErrPaginationLoop {
+		t.Errorf("Client fetch all error, expected '%v', got '%v'", ErrPaginationLoop, err)
+	}
+}
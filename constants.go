@@ -7,8 +7,17 @@ const (
 	// HypercatMediaType is the default mime type of Hypercat resources
 	HypercatMediaType = "application/vnd.hypercat.catalogue+json"
 
-	// DescriptionRel is the URI for the hasDescription relationship
-	DescriptionRel = "urn:X-hypercat:rels:hasDescription:en"
+	// DefaultLanguage is the language tag of the Description field of catalogues
+	// and items
+	DefaultLanguage = "en"
+
+	// DescriptionRelPrefix is the prefix of the URIs for the hasDescription
+	// relationship, which are completed by a language tag
+	DescriptionRelPrefix = "urn:X-hypercat:rels:hasDescription:"
+
+	// DescriptionRel is the URI for the hasDescription relationship in the
+	// default language
+	DescriptionRel = DescriptionRelPrefix + DefaultLanguage
 
 	// ContentTypeRel is the URI for the isContentType relationship
 	ContentTypeRel = "urn:X-hypercat:rels:isContentType"
@@ -16,6 +25,18 @@ const (
 	// HomepageRel is the URI for hasHomepage relationship
 	HomepageRel = "urn:X-hypercat:rels:hasHomepage"
 
+	// LastUpdatedRel is the URI for the lastUpdated relationship, whose value is
+	// an ISO 8601 timestamp of the last modification of an item or catalogue
+	LastUpdatedRel = "urn:X-hypercat:rels:lastUpdated"
+
+	// NextPageRel is the URI of the relationship linking one page of a
+	// paginated catalogue to the following page
+	NextPageRel = "urn:X-hypercat:rels:nextPage"
+
+	// SignatureRel is the URI of the relationship whose value is a detached JWS
+	// signing the canonical serialization of a catalogue or item
+	SignatureRel = "urn:X-hypercat:rels:hasSignature"
+
 	// ContainsContentTypeRel is the URI for the containsContentType relationship
 	ContainsContentTypeRel = "urn:X-hypercat:rels:containsContentType"
 
@@ -23,7 +44,7 @@ const (
 	SupportsSearchRel = "urn:X-hypercat:rels:supportsSearch"
 
 	// SimpleSearchVal is the required value for catalogues that support Hypercat simple search.
-	SimpleSearchVal = "urn:X­hypercat:search:simple"
+	SimpleSearchVal = "urn:X-hypercat:search:simple"
 
 	// GeoBoundSearchVal is the required value for catalogues that support geographic bounding box search
 	GeoBoundSearchVal = "urn:X-hypercat:search:geobound"
@@ -0,0 +1,219 @@
+package hypercat
+
+import (
+	"sort"
+	"strings"
+)
+
+// descriptionLang returns the language tag of a hasDescription rel, and whether
+// the given rel is a hasDescription rel.
+func descriptionLang(rel string) (string, bool) {
+	if !strings.HasPrefix(rel, DescriptionRelPrefix) || len(rel) == len(DescriptionRelPrefix) {
+		return "", false
+	}
+
+	return rel[len(DescriptionRelPrefix):], true
+}
+
+// isDefaultLanguage reports whether the given language tag is DefaultLanguage.
+func isDefaultLanguage(lang string) bool {
+	return strings.EqualFold(lang, DefaultLanguage)
+}
+
+// storeDescription records a description parsed from a hasDescription rel in
+// either the default description or the map of other languages, ignoring
+// empty values.
+func storeDescription(def *string, others *map[string]string, lang, text string) {
+	if text == "" {
+		return
+	}
+
+	if isDefaultLanguage(lang) {
+		*def = text
+		return
+	}
+
+	if *others == nil {
+		*others = make(map[string]string)
+	}
+
+	(*others)[lang] = text
+}
+
+// setDescription sets or, if text is empty, removes the description in the
+// given language.
+func setDescription(def *string, others *map[string]string, lang, text string) {
+	if isDefaultLanguage(lang) {
+		*def = text
+		return
+	}
+
+	for existing := range *others {
+		if strings.EqualFold(existing, lang) {
+			delete(*others, existing)
+		}
+	}
+
+	storeDescription(def, others, lang, text)
+}
+
+// exactDescription returns the description in exactly the given language, or
+// an empty string if there is none.
+func exactDescription(def string, others map[string]string, lang string) string {
+	if isDefaultLanguage(lang) {
+		return def
+	}
+
+	for existing, text := range others {
+		if strings.EqualFold(existing, lang) {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// lookupDescription returns the best description for the given language
+// preferences. Each preferred tag is tried in turn, followed by its
+// progressively shorter prefixes (so "de-CH" falls back to "de"). If none
+// match, the description in DefaultLanguage is returned, followed by the
+// description in the alphabetically first language.
+func lookupDescription(def string, others map[string]string, langs []string) string {
+	for _, lang := range langs {
+		for tag := lang; tag != ""; tag = parentTag(tag) {
+			if isDefaultLanguage(tag) && def != "" {
+				return def
+			}
+
+			for existing, text := range others {
+				if strings.EqualFold(existing, tag) {
+					return text
+				}
+			}
+		}
+	}
+
+	if def != "" {
+		return def
+	}
+
+	languages := descriptionLanguages(def, others)
+	if len(languages) == 0 {
+		return ""
+	}
+
+	return others[languages[0]]
+}
+
+// parentTag returns the language tag with its last subtag removed, or "" if it
+// has a single subtag.
+func parentTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+
+	return tag[:i]
+}
+
+// descriptionLanguages returns the language tags of all descriptions, with
+// DefaultLanguage first followed by the others in alphabetical order.
+func descriptionLanguages(def string, others map[string]string) []string {
+	languages := make([]string, 0, len(others)+1)
+
+	for lang := range others {
+		languages = append(languages, lang)
+	}
+
+	sort.Strings(languages)
+
+	if def != "" {
+		languages = append([]string{DefaultLanguage}, languages...)
+	}
+
+	return languages
+}
+
+// descriptionMetadata returns the hasDescription rels representing all
+// descriptions, ordered as descriptionLanguages.
+func descriptionMetadata(def string, others map[string]string) Metadata {
+	metadata := Metadata{}
+
+	for _, lang := range descriptionLanguages(def, others) {
+		if isDefaultLanguage(lang) {
+			metadata = append(metadata, Rel{Rel: DescriptionRel, Val: def})
+		} else {
+			metadata = append(metadata, Rel{Rel: DescriptionRelPrefix + lang, Val: others[lang]})
+		}
+	}
+
+	return metadata
+}
+
+// copyDescriptions returns a copy of a map of descriptions.
+func copyDescriptions(others map[string]string) map[string]string {
+	if others == nil {
+		return nil
+	}
+
+	c := make(map[string]string, len(others))
+
+	for lang, text := range others {
+		c[lang] = text
+	}
+
+	return c
+}
+
+// DescriptionIn returns the item's description in the first available of the
+// given languages, falling back to less specific language tags, then to the
+// Description in DefaultLanguage, and finally to any other description.
+func (item *Item) DescriptionIn(langs ...string) string {
+	return lookupDescription(item.Description, item.Descriptions, langs)
+}
+
+// SetDescriptionIn sets the item's description in the given language. Setting
+// the description in DefaultLanguage sets the Description field, and setting
+// an empty description removes it.
+func (item *Item) SetDescriptionIn(lang, text string) {
+	setDescription(&item.Description, &item.Descriptions, lang, text)
+}
+
+// Languages returns the language tags of all the item's descriptions, with
+// DefaultLanguage first.
+func (item *Item) Languages() []string {
+	return descriptionLanguages(item.Description, item.Descriptions)
+}
+
+// DescriptionIn returns the catalogue's description in the first available of
+// the given languages. See Item.DescriptionIn for details of the fallback
+// chain.
+func (h *Hypercat) DescriptionIn(langs ...string) string {
+	return lookupDescription(h.Description, h.Descriptions, langs)
+}
+
+// SetDescriptionIn sets the catalogue's description in the given language.
+// Setting the description in DefaultLanguage sets the Description field, and
+// setting an empty description removes it.
+func (h *Hypercat) SetDescriptionIn(lang, text string) {
+	old := exactDescription(h.Description, h.Descriptions, lang)
+
+	setDescription(&h.Description, &h.Descriptions, lang, text)
+	h.touch(nil)
+
+	if text == "" {
+		if old != "" {
+			h.publish(Event{Type: RelRemoved, Rel: NewRel(DescriptionRelPrefix+lang, old)})
+		}
+
+		return
+	}
+
+	h.publish(Event{Type: RelReplaced, Rel: NewRel(DescriptionRelPrefix+lang, text)})
+}
+
+// Languages returns the language tags of all the catalogue's descriptions,
+// with DefaultLanguage first.
+func (h *Hypercat) Languages() []string {
+	return descriptionLanguages(h.Description, h.Descriptions)
+}
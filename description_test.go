@@ -0,0 +1,120 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionIn(t *testing.T) {
+	item := NewItem("/foo", "English")
+	item.SetDescriptionIn("de", "Deutsch")
+	item.SetDescriptionIn("fr-BE", "Français (Belgique)")
+	item.SetDescriptionIn("nl", "Nederlands")
+
+	var testcases = []struct {
+		langs    []string
+		expected string
+	}{
+		{nil, "English"},
+		{[]string{"de"}, "Deutsch"},
+		{[]string{"DE"}, "Deutsch"},
+		{[]string{"de-CH"}, "Deutsch"},
+		{[]string{"fr-be"}, "Français (Belgique)"},
+		{[]string{"fr"}, "English"},
+		{[]string{"it", "nl"}, "Nederlands"},
+		{[]string{"en-GB", "de"}, "English"},
+	}
+
+	for _, testcase := range testcases {
+		got := item.DescriptionIn(testcase.langs...)
+
+		if got != testcase.expected {
+			t.Errorf("DescriptionIn error for '%v', expected '%v', got '%v'", testcase.langs, testcase.expected, got)
+		}
+	}
+
+	expected := []string{"en", "de", "fr-BE", "nl"}
+
+	if !reflect.DeepEqual(item.Languages(), expected) {
+		t.Errorf("Languages error, expected '%v', got '%v'", expected, item.Languages())
+	}
+
+	item.SetDescriptionIn("NL", "")
+	item.SetDescriptionIn("en", "")
+
+	if item.Description != "" || len(item.Descriptions) != 2 {
+		t.Errorf("SetDescriptionIn should remove empty descriptions, got '%v' '%v'", item.Description, item.Descriptions)
+	}
+
+	if item.DescriptionIn("it") != "Deutsch" {
+		t.Errorf("DescriptionIn should fall back to the first language, got '%v'", item.DescriptionIn("it"))
+	}
+}
+
+func TestLocalizedDescriptionMarshalling(t *testing.T) {
+	cat := NewHypercat("Catalogue")
+	cat.SetDescriptionIn("it", "Catalogo")
+
+	item := NewItem("/foo", "")
+	item.SetDescriptionIn("fr", "Article")
+	item.SetDescriptionIn("de", "Artikel")
+	cat.AddItem(item)
+
+	bytes, err := json.Marshal(cat)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"items":[{"href":"/foo","item-metadata":[` +
+		`{"rel":"urn:X-hypercat:rels:hasDescription:de","val":"Artikel"},` +
+		`{"rel":"urn:X-hypercat:rels:hasDescription:fr","val":"Article"}]}],` +
+		`"catalogue-metadata":[` +
+		`{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue"},` +
+		`{"rel":"urn:X-hypercat:rels:hasDescription:it","val":"Catalogo"},` +
+		`{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}]}`
+
+	if string(bytes) != expected {
+		t.Errorf("Hypercat marshalling error, expected '%v', got '%v'", expected, string(bytes))
+	}
+
+	parsed, err := Parse(strings.NewReader(string(bytes)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if parsed.DescriptionIn("it") != "Catalogo" || parsed.Description != "Catalogue" {
+		t.Errorf("Hypercat unmarshalling error, got '%v' '%v'", parsed.Description, parsed.Descriptions)
+	}
+
+	if parsed.Items[0].Description != "" || parsed.Items[0].DescriptionIn("de") != "Artikel" {
+		t.Errorf("Item unmarshalling error, got '%v' '%v'", parsed.Items[0].Description, parsed.Items[0].Descriptions)
+	}
+
+	if len(parsed.Metadata) != 0 || len(parsed.Items[0].Metadata) != 0 {
+		t.Errorf("Descriptions should not be stored as metadata")
+	}
+}
+
+func TestSetCatalogueDescriptionIn(t *testing.T) {
+	cat := NewHypercat("Catalogue")
+	cat.Feed = NewFeed(10)
+
+	cat.SetDescriptionIn("de", "Katalog")
+
+	events, _ := cat.Feed.Since(0)
+
+	if len(events) != 1 || events[0].Rel.Rel != DescriptionRelPrefix+"de" {
+		t.Errorf("SetDescriptionIn should publish an event, got '%v'", events)
+	}
+
+	cat.SetDescriptionIn("DE", "")
+	cat.SetDescriptionIn("fr", "")
+
+	events, _ = cat.Feed.Since(1)
+
+	if len(events) != 1 || events[0].Type != RelRemoved || events[0].Rel.Val != "Katalog" {
+		t.Errorf("SetDescriptionIn should publish the removal of a description, got '%v'", events)
+	}
+}
@@ -2,6 +2,9 @@ package hypercat
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 )
 
 var (
@@ -13,6 +16,10 @@ var (
 	// within a catalogue but no item with that href is defined
 	ErrHrefNotFound = errors.New("An item with that href does not exist within the catalogue")
 
+	// ErrHrefForbidden is returned by a Handler when the principal making a
+	// request attempts to add an item with a href hidden from them by its ACL.
+	ErrHrefForbidden = errors.New("The href is not accessible to the principal")
+
 	// ErrMissingDescriptionRel is returned if we fail to find the required
 	// description rel when unmarshalling from a JSON string.
 	ErrMissingDescriptionRel = errors.New(`"` + DescriptionRel + `" is a mandatory metadata relation`)
@@ -24,4 +31,219 @@ var (
 	// ErrMissingHref is returned if the href for an item is not defined when
 	// unmarshalling from a JSON string
 	ErrMissingHref = errors.New(`"href" is a mandatory attribute`)
+
+	// ErrHrefMismatch is returned when the href of an item sent to replace a
+	// resource doesn't match the href of the resource being replaced.
+	ErrHrefMismatch = errors.New("The href of the item does not match the requested href")
+
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("The pagination cursor is invalid")
+
+	// ErrPaginationLoop is returned by a Client when following the pages of a
+	// catalogue leads back to a page that was already fetched.
+	ErrPaginationLoop = errors.New("The catalogue pages link back to a page that was already fetched")
+
+	// ErrRelNotFound is returned when attempting to read the value of a Rel that
+	// is not defined within the metadata.
+	ErrRelNotFound = errors.New("The requested rel is not defined within the metadata")
+
+	// ErrMissingSignature is returned when verifying a catalogue or item which
+	// doesn't carry a signature.
+	ErrMissingSignature = errors.New(`"` + SignatureRel + `" is required to verify the signature`)
+
+	// ErrInvalidSignature is returned when the signature of a catalogue or item
+	// is malformed or doesn't match its content.
+	ErrInvalidSignature = errors.New("The signature is not valid")
+
+	// ErrUnsupportedKey is returned when attempting to sign or verify using a key
+	// type other than Ed25519 or ECDSA.
+	ErrUnsupportedKey = errors.New("Only Ed25519 and ECDSA keys are supported")
+
+	// ErrUnsupportedType is returned when mapping between an Item and a value
+	// whose type, or the type of one of its tagged fields, isn't supported.
+	ErrUnsupportedType = errors.New("The type cannot be mapped to item metadata")
+
+	// ErrDuplicateRel is returned when registering a rel definition for a rel
+	// that is already defined within the registry.
+	ErrDuplicateRel = errors.New("A definition for that rel already exists within the registry")
+
+	// ErrTypeMismatch is returned when reading the value of a rel as a type other
+	// than the one it is registered with.
+	ErrTypeMismatch = errors.New("The rel is registered with a different value type")
+
+	// ErrValueNotAllowed is returned when the value of a rel isn't one of the
+	// values allowed by its definition.
+	ErrValueNotAllowed = errors.New("The value is not allowed for this rel")
+
+	// ErrTooFewValues is returned when a rel occurs fewer times than required by
+	// its definition.
+	ErrTooFewValues = errors.New("The rel occurs fewer times than required")
+
+	// ErrTooManyValues is returned when a rel occurs more times than permitted by
+	// its definition.
+	ErrTooManyValues = errors.New("The rel occurs more times than permitted")
+
+	// ErrMissingBaseURL is returned when resolving or rewriting item hrefs
+	// requires a base URL that hasn't been set.
+	ErrMissingBaseURL = errors.New("The catalogue has no base URL")
+
+	// ErrInvalidCredentials is returned by an Authenticator when a request
+	// carries credentials that are not valid.
+	ErrInvalidCredentials = errors.New("The supplied credentials are not valid")
+
+	// ErrInvalidSearch is returned when a search query is malformed.
+	ErrInvalidSearch = errors.New("The search query is invalid")
+
+	// ErrUnsupportedSearch is returned when a search uses a mode that the
+	// catalogue server doesn't support.
+	ErrUnsupportedSearch = errors.New("The search mode is not supported")
+
+	// ErrUnknownField is returned when parsing in strict mode encounters a JSON
+	// field not defined by the Hypercat specification.
+	ErrUnknownField = errors.New("The document contains an unknown field")
+
+	// ErrDuplicateDescription is returned when parsing in strict mode encounters
+	// more than one description rel for the same language.
+	ErrDuplicateDescription = errors.New("The metadata contains more than one description in the same language")
+
+	// ErrSequenceExpired is returned when a consumer attempts to resume a change
+	// feed from a sequence number whose following events have already been
+	// discarded.
+	ErrSequenceExpired = errors.New("The requested sequence number is no longer retained by the feed")
+
+	// ErrSequenceInFuture is returned when a consumer attempts to resume a change
+	// feed from a sequence number that has not yet been published.
+	ErrSequenceInFuture = errors.New("The requested sequence number has not been published by the feed")
+
+	// ErrSubscriberLagged is reported by a Subscription that was dropped because
+	// its consumer fell too far behind the feed.
+	ErrSubscriberLagged = errors.New("The subscriber fell too far behind the feed and was dropped")
 )
+
+// StatusError is returned by a Client when a server responds to a request
+// with an unexpected HTTP status code.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Unexpected HTTP status %d (%s) from %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
+
+// ParseError records the position within a document at which parsing a
+// catalogue failed. Index is the index of the item that failed, or -1 if the
+// failure was within the catalogue itself, and Href is the href of the item
+// when known. Offset is the byte offset of the item within the document, or
+// for catalogue failures that of the field or JSON syntax error responsible.
+type ParseError struct {
+	Index  int
+	Href   string
+	Offset int64
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	switch {
+	case e.Index < 0:
+		return fmt.Sprintf("catalogue (offset %d): %v", e.Offset, e.Err)
+	case e.Href == "":
+		return fmt.Sprintf("item %d (offset %d): %v", e.Index, e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("item %d %q (offset %d): %v", e.Index, e.Href, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// RelError records a failure to read or convert the value of a Rel.
+type RelError struct {
+	Rel string
+	Val string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RelError) Error() string {
+	switch e.Err {
+	case ErrRelNotFound:
+		return fmt.Sprintf("%q is not defined within the metadata", e.Rel)
+	case ErrTooFewValues, ErrTooManyValues:
+		return fmt.Sprintf("%q: %v", e.Rel, e.Err)
+	}
+
+	return fmt.Sprintf("Invalid value %q for %q: %v", e.Val, e.Rel, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RelError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError describes a single violation of a registry's rel
+// definitions. Href is empty for violations within the catalogue metadata.
+type ValidationError struct {
+	Href string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Href == "" {
+		return "catalogue: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("item %q: %v", e.Href, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is the list of violations returned when validating a
+// catalogue or item.
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// BuildError is returned by Builder.Build, listing every error encountered
+// while building a catalogue.
+type BuildError []error
+
+// Error implements the error interface.
+func (e BuildError) Error() string {
+	msgs := make([]string, len(e))
+
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the listed errors matches target, allowing
+// errors.Is to be used with a BuildError.
+func (e BuildError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package hypercat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// jsonField is a known field of a JSON object being encoded.
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// marshalObject encodes a JSON object containing the given known fields in
+// order, followed by any extra fields sorted by key. In canonical mode values
+// are encoded with canonicalEncode, and extra values are normalized so that
+// any objects within them also have sorted keys.
+func marshalObject(known []jsonField, extra map[string]json.RawMessage, canonical bool) ([]byte, error) {
+	encode := json.Marshal
+	if canonical {
+		encode = canonicalEncode
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	write := func(key string, value []byte) error {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		k, err := encode(key)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(value)
+
+		return nil
+	}
+
+	for _, field := range known {
+		value, err := encode(field.value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = write(field.key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range extraKeys(extra) {
+		value, err := normalizeExtra(extra[key], canonical)
+		if err != nil {
+			return nil, err
+		}
+
+		err = write(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// normalizeExtra returns the compacted encoding of an extra field's value, or
+// in canonical mode its canonical encoding.
+func normalizeExtra(raw json.RawMessage, canonical bool) ([]byte, error) {
+	if !canonical {
+		var buf bytes.Buffer
+
+		err := json.Compact(&buf, raw)
+
+		return buf.Bytes(), err
+	}
+
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalEncode(v)
+}
+
+// unmarshalObject decodes a JSON object, storing the values of the known keys
+// in the given targets and returning the remaining fields, or nil if there
+// are none.
+func unmarshalObject(b []byte, known map[string]interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, target := range known {
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		delete(fields, key)
+
+		err = json.Unmarshal(raw, target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+// extraKeys returns the keys of a set of extra fields in sorted order.
+func extraKeys(extra map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(extra))
+
+	for key := range extra {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// copyExtra returns a copy of a set of extra fields.
+func copyExtra(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	if extra == nil {
+		return nil
+	}
+
+	c := make(map[string]json.RawMessage, len(extra))
+
+	for key, raw := range extra {
+		c[key] = raw
+	}
+
+	return c
+}
+
+// unknownFieldError returns an error describing the first of a set of extra
+// fields, or nil if there are none.
+func unknownFieldError(extra map[string]json.RawMessage) error {
+	keys := extraKeys(extra)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownField, keys[0])
+}
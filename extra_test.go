@@ -0,0 +1,70 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const extraDocument = `{"items":[{"href":"/foo","vendor:flags":[1, 2.50],"item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item description"}]}],` +
+	`"catalogue-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue description"},{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}],` +
+	`"vendor:info":{"z":true,"a":"<b>"},"vendor:count":3}`
+
+func TestExtraFieldsRoundTrip(t *testing.T) {
+	cat, err := Parse(strings.NewReader(extraDocument))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cat.Extra) != 2 || len(cat.Items[0].Extra) != 1 {
+		t.Fatalf("Unknown fields should be preserved, got '%v' and '%v'", cat.Extra, cat.Items[0].Extra)
+	}
+
+	bytes, err := json.Marshal(cat)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"items":[{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item description"}],"vendor:flags":[1,2.50]}],` +
+		`"catalogue-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue description"},{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}],` +
+		`"vendor:count":3,"vendor:info":{"z":true,"a":"\u003cb\u003e"}}`
+
+	if string(bytes) != expected {
+		t.Errorf("Hypercat marshalling error, expected '%v', got '%v'", expected, string(bytes))
+	}
+
+	canonical, err := cat.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = `{"items":[{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Item description"}],"vendor:flags":[1,2.50]}],` +
+		`"catalogue-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue description"},{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}],` +
+		`"vendor:count":3,"vendor:info":{"a":"<b>","z":true}}`
+
+	if string(canonical) != expected {
+		t.Errorf("Canonical encoding error, expected '%v', got '%v'", expected, string(canonical))
+	}
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	_, err := Parse(strings.NewReader(extraDocument), DisallowUnknownFields())
+	if !errors.Is(err, ErrUnknownField) || !strings.Contains(err.Error(), "vendor:count") {
+		t.Errorf("Parse error, expected '%v', got '%v'", ErrUnknownField, err)
+	}
+
+	document := strings.Replace(extraDocument, `,"vendor:info":{"z":true,"a":"<b>"},"vendor:count":3`, "", 1)
+
+	_, err = Parse(strings.NewReader(document), DisallowUnknownFields())
+	if !errors.Is(err, ErrUnknownField) || !strings.Contains(err.Error(), "vendor:flags") {
+		t.Errorf("Parse error, expected '%v', got '%v'", ErrUnknownField, err)
+	}
+
+	bytes, _ := json.Marshal(NewHypercat("description"))
+
+	_, err = Parse(strings.NewReader(string(bytes)), DisallowUnknownFields())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
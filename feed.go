@@ -0,0 +1,358 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// EventType identifies the kind of catalogue mutation described by an Event.
+type EventType string
+
+const (
+	// ItemAdded is the type of events emitted when an item is added to a
+	// catalogue.
+	ItemAdded EventType = "item-added"
+
+	// ItemReplaced is the type of events emitted when an item within a
+	// catalogue is replaced.
+	ItemReplaced EventType = "item-replaced"
+
+	// ItemRemoved is the type of events emitted when an item is removed from a
+	// catalogue. Events of this type carry the href but not the item.
+	ItemRemoved EventType = "item-removed"
+
+	// RelAdded is the type of events emitted when a Rel is added to the
+	// catalogue metadata.
+	RelAdded EventType = "rel-added"
+
+	// RelReplaced is the type of events emitted when a Rel within the
+	// catalogue metadata is replaced.
+	RelReplaced EventType = "rel-replaced"
+
+	// RelRemoved is the type of events emitted when a Rel is removed from the
+	// catalogue metadata. One event is emitted for each value removed.
+	RelRemoved EventType = "rel-removed"
+)
+
+// DefaultFeedCapacity is the number of events retained by a Feed created with
+// a non positive capacity.
+const DefaultFeedCapacity = 1024
+
+// Event is the representation of a single mutation applied to a catalogue.
+// Events are numbered by their Feed with strictly increasing sequence numbers
+// starting from 1.
+type Event struct {
+	Seq  uint64    `json:"seq"`
+	Type EventType `json:"type"`
+	Href string    `json:"href,omitempty"`
+	Item *Item     `json:"item,omitempty"`
+	Rel  *Rel      `json:"rel,omitempty"`
+}
+
+// Feed is a change feed recording the mutations applied to a catalogue. A Feed
+// is attached to a catalogue by setting its Feed field, after which every
+// mutation made through the catalogue API is published to it.
+//
+// A Feed retains a bounded backlog of recent events, which allows consumers to
+// resume from the last sequence number they saw. It is safe for concurrent
+// use.
+type Feed struct {
+	mu       sync.Mutex
+	seq      uint64
+	capacity int
+	events   []Event
+	trimmed  bool
+	subs     map[*Subscription]struct{}
+}
+
+// NewFeed is a constructor function that creates and returns a Feed instance.
+// Accepts the number of events to retain for resuming consumers as a
+// parameter, falling back to DefaultFeedCapacity if this is not positive.
+func NewFeed(capacity int) *Feed {
+	if capacity <= 0 {
+		capacity = DefaultFeedCapacity
+	}
+
+	return &Feed{
+		capacity: capacity,
+		events:   make([]Event, 0, capacity),
+		subs:     make(map[*Subscription]struct{}),
+	}
+}
+
+// Seq returns the sequence number of the most recently published event, or 0
+// if nothing has been published yet.
+func (f *Feed) Seq() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.seq
+}
+
+// Since returns all retained events with a sequence number greater than seq.
+// Passing 0 returns the full history. Returns ErrSequenceExpired if events
+// following seq have already been discarded from the backlog, in which case
+// the consumer must resynchronise from the full catalogue.
+func (f *Feed) Since(seq uint64) ([]Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.since(seq)
+}
+
+// since is the lock free implementation of Since.
+func (f *Feed) since(seq uint64) ([]Event, error) {
+	if seq > f.seq {
+		return nil, ErrSequenceInFuture
+	}
+
+	if f.trimmed && seq+1 < f.events[0].Seq {
+		return nil, ErrSequenceExpired
+	}
+
+	events := []Event{}
+
+	for _, ev := range f.events {
+		if ev.Seq > seq {
+			events = append(events, ev)
+		}
+	}
+
+	return events, nil
+}
+
+// Subscribe returns a Subscription that delivers every event with a sequence
+// number greater than seq, first replaying the retained backlog and then
+// delivering new events as they are published. Returns the same errors as
+// Since.
+func (f *Feed) Subscribe(seq uint64) (*Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	backlog, err := f.since(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan Event)
+
+	sub := &Subscription{
+		C:       c,
+		c:       c,
+		feed:    f,
+		pending: backlog,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	f.subs[sub] = struct{}{}
+
+	go sub.run()
+
+	return sub, nil
+}
+
+// publish assigns the next sequence number to the given event, appends it to
+// the backlog and delivers it to all current subscribers.
+func (f *Feed) publish(ev Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	ev.Seq = f.seq
+
+	if len(f.events) == f.capacity {
+		copy(f.events, f.events[1:])
+		f.events = f.events[:len(f.events)-1]
+		f.trimmed = true
+	}
+
+	f.events = append(f.events, ev)
+
+	for sub := range f.subs {
+		sub.enqueue(ev)
+	}
+}
+
+// remove detaches the given subscription from the feed.
+func (f *Feed) remove(sub *Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.subs, sub)
+}
+
+// ServeHTTP implements http.Handler, streaming the feed to the client as
+// Server-Sent Events. Each event is sent with its sequence number as the event
+// id, so clients reconnecting with a Last-Event-ID header (or a "since" query
+// parameter) resume where they left off. Responds with 410 Gone if the
+// requested position has already been discarded from the backlog.
+//
+// Every event is streamed, including the full items of item events, so the
+// feed of a catalogue served with an ACL should be served through
+// Handler.ServeFeed instead.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.serve(w, r, func(Event) bool { return true })
+}
+
+// serve streams the events of the feed for which keep returns true, as
+// described by ServeHTTP.
+func (f *Feed) serve(w http.ResponseWriter, r *http.Request, keep func(ev Event) bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	from := r.Header.Get("Last-Event-ID")
+	if from == "" {
+		from = r.URL.Query().Get("since")
+	}
+
+	var seq uint64
+
+	if from != "" {
+		var err error
+
+		seq, err = strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid event id", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sub, err := f.Subscribe(seq)
+	if err == ErrSequenceExpired {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+
+			if !keep(ev) {
+				continue
+			}
+
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return
+			}
+
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+			if err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+// Subscription is a live stream of events from a Feed. Events are delivered
+// in order on C. A subscriber that falls more than the feed capacity behind is
+// dropped: C is closed and Err returns ErrSubscriberLagged, after which the
+// consumer may resubscribe from the last sequence number it received.
+type Subscription struct {
+	C <-chan Event
+
+	c       chan Event
+	feed    *Feed
+	mu      sync.Mutex
+	pending []Event
+	err     error
+	notify  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Close detaches the subscription from its feed and closes C.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.feed.remove(s)
+		close(s.done)
+	})
+}
+
+// Err returns the reason the subscription was terminated by its feed, or nil
+// if it is still active or was closed by the consumer.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// enqueue queues an event for delivery, dropping the subscriber if it has
+// fallen too far behind. It is called with the feed lock held.
+func (s *Subscription) enqueue(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return
+	}
+
+	if len(s.pending) >= s.feed.capacity {
+		s.err = ErrSubscriberLagged
+		s.pending = nil
+		delete(s.feed.subs, s)
+		s.once.Do(func() { close(s.done) })
+		return
+	}
+
+	s.pending = append(s.pending, ev)
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers pending events on the subscription channel until the
+// subscription is closed.
+func (s *Subscription) run() {
+	defer close(s.c)
+
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+
+			select {
+			case <-s.notify:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		ev := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.c <- ev:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package hypercat
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedRecordsMutations(t *testing.T) {
+	cat := NewHypercat("description")
+	cat.Feed = NewFeed(10)
+
+	cat.AddRel("relation", "value")
+	cat.ReplaceRel("relation", "newvalue")
+	cat.ReplaceRel("missing", "value")
+
+	err := cat.AddItem(NewItem("/foo", "Item1 description"))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = cat.ReplaceItem(NewItem("/foo", "Item2 description"))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	events, err := cat.Feed.Since(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []EventType{RelAdded, RelReplaced, ItemAdded, ItemReplaced}
+
+	if len(events) != len(expected) {
+		t.Fatalf("Feed error, expected %v events, got %v", len(expected), len(events))
+	}
+
+	for i, ev := range events {
+		if ev.Seq != uint64(i+1) {
+			t.Errorf("Feed sequence error, expected '%v', got '%v'", i+1, ev.Seq)
+		}
+
+		if ev.Type != expected[i] {
+			t.Errorf("Feed event type error, expected '%v', got '%v'", expected[i], ev.Type)
+		}
+	}
+
+	if events[3].Item.Description != "Item2 description" {
+		t.Errorf("Feed event item error, got '%v'", events[3].Item.Description)
+	}
+
+	if cat.Feed.Seq() != 4 {
+		t.Errorf("Feed sequence error, expected '%v', got '%v'", 4, cat.Feed.Seq())
+	}
+}
+
+func TestFeedSince(t *testing.T) {
+	feed := NewFeed(3)
+
+	for i := 0; i < 5; i++ {
+		feed.publish(Event{Type: RelAdded})
+	}
+
+	events, err := feed.Since(3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seqs := []uint64{}
+	for _, ev := range events {
+		seqs = append(seqs, ev.Seq)
+	}
+
+	if !reflect.DeepEqual(seqs, []uint64{4, 5}) {
+		t.Errorf("Feed since error, expected '%v', got '%v'", []uint64{4, 5}, seqs)
+	}
+
+	_, err = feed.Since(2)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	_, err = feed.Since(1)
+	if err != ErrSequenceExpired {
+		t.Errorf("Feed since error, expected '%v', got '%v'", ErrSequenceExpired, err)
+	}
+
+	_, err = feed.Since(6)
+	if err != ErrSequenceInFuture {
+		t.Errorf("Feed since error, expected '%v', got '%v'", ErrSequenceInFuture, err)
+	}
+}
+
+func TestFeedSubscribe(t *testing.T) {
+	cat := NewHypercat("description")
+	cat.Feed = NewFeed(10)
+
+	cat.AddRel("relation1", "value")
+	cat.AddRel("relation2", "value")
+
+	sub, err := cat.Feed.Subscribe(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer sub.Close()
+
+	cat.AddRel("relation3", "value")
+
+	for _, expected := range []string{"relation2", "relation3"} {
+		select {
+		case ev := <-sub.C:
+			if ev.Rel.Rel != expected {
+				t.Errorf("Subscription error, expected '%v', got '%v'", expected, ev.Rel.Rel)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for event")
+		}
+	}
+}
+
+func TestFeedSubscriberLagged(t *testing.T) {
+	feed := NewFeed(2)
+
+	sub, err := feed.Subscribe(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// nobody reads from sub.C, so at most one of these can be in flight
+	sub.mu.Lock()
+	sub.pending = append(sub.pending, Event{}, Event{}, Event{})
+	sub.mu.Unlock()
+
+	feed.publish(Event{Type: RelAdded})
+
+	if sub.Err() != ErrSubscriberLagged {
+		t.Errorf("Subscription error, expected '%v', got '%v'", ErrSubscriberLagged, sub.Err())
+	}
+
+	for range sub.C {
+	}
+}
+
+func TestFeedServeHTTP(t *testing.T) {
+	feed := NewFeed(10)
+	feed.publish(Event{Type: RelAdded, Rel: NewRel("relation1", "value")})
+	feed.publish(Event{Type: RelAdded, Rel: NewRel("relation2", "value")})
+
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("SSE content type error, got '%v'", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	expected := []string{
+		"id: 2",
+		"event: rel-added",
+		`data: {"seq":2,"type":"rel-added","rel":{"rel":"relation2","val":"value"}}`,
+	}
+
+	for _, line := range expected {
+		got, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if strings.TrimSpace(got) != line {
+			t.Errorf("SSE error, expected '%v', got '%v'", line, strings.TrimSpace(got))
+		}
+	}
+}
+
+func TestFeedServeHTTPExpired(t *testing.T) {
+	feed := NewFeed(1)
+	feed.publish(Event{Type: RelAdded})
+	feed.publish(Event{Type: RelAdded})
+
+	req := httptest.NewRequest("GET", "/events?since=0", nil)
+	w := httptest.NewRecorder()
+
+	feed.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGone {
+		t.Errorf("SSE status error, expected '%v', got '%v'", http.StatusGone, w.Code)
+	}
+}
@@ -0,0 +1,616 @@
+package hypercat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler is an http.Handler that serves a catalogue following the Hypercat
+// HTTP API: GET returns the catalogue, POST adds the item contained in the
+// request body, PUT replaces the item identified by the "href" query
+// parameter with the item contained in the request body, and DELETE removes
+// the item identified by the "href" query parameter.
+//
+// GET requests may be paginated using the "limit" query parameter along with
+// either an "offset" or an opaque "cursor" parameter, where an empty cursor
+// requests the first page of cursor based pagination. Each page carries a
+// NextPageRel in its catalogue metadata linking to the following page.
+//
+// Responses carry a strong ETag computed by Hypercat.ETag, along with a
+// Last-Modified header if the catalogue has a LastUpdatedRel. GET requests
+// with a matching If-None-Match or If-Modified-Since header receive 304 Not
+// Modified, and write requests with an If-Match header that no longer matches
+// the catalogue are rejected with 412 Precondition Failed.
+//
+// Requests are authenticated by the optional Authenticator, and each
+// operation checked against the optional Authorizer. Anonymous requests that
+// aren't authorized, or that carry invalid credentials, receive 401
+// Unauthorized, and authenticated requests that aren't authorized receive 403
+// Forbidden. The authenticated principal is available to later stages through
+// PrincipalFromContext.
+//
+// Search modes listed in SearchModes are advertised by SupportsSearchRel rels
+// in the served catalogue, replacing any it already carries unless they
+// already advertise the same modes, and GET requests
+// using their query parameters receive the matching items, as described by
+// Hypercat.Search. If MultiSearchVal is listed, POST requests with a "multi"
+// query parameter and a MultiSearch body are answered with the result of
+// Hypercat.MultiSearch. Searches using other modes are rejected with 400 Bad
+// Request. Search results may be paginated like the catalogue itself. A
+// signed catalogue must itself carry SupportsSearchRel rels matching
+// SearchModes, as rewriting them would invalidate its signature.
+//
+// If an ACL is attached, each principal is served the view of the catalogue
+// returned by ACL.View, with an ETag computed from that view. Items hidden
+// from the principal can't be replaced or deleted, and are reported as not
+// found, while adding an item with a hidden href is forbidden whether or not
+// it exists. Update attaches the ACL to the catalogue as an Index, so that
+// its entries follow items whose hrefs are rewritten within Update. The
+// catalogue's Feed streams every item unfiltered, so it should be served
+// through ServeFeed rather than directly.
+//
+// Handler serialises all access to its catalogue, which must therefore only be
+// modified through Update while it is being served.
+type Handler struct {
+	mu  sync.RWMutex
+	cat *Hypercat
+
+	Authenticator Authenticator // Optional source of the principal making each request.
+	Authorizer    Authorizer    // Optional check of each operation, which allows everything if nil.
+	ACL           *ACL          // Optional access control list filtering the items visible to each principal.
+	SearchModes   []string      // Search modes answered and advertised, from the SupportsSearchRel values.
+}
+
+// NewHandler is a constructor function that creates and returns a Handler
+// serving the given catalogue.
+func NewHandler(cat *Hypercat) *Handler {
+	return &Handler{cat: cat}
+}
+
+// Update calls fn with the served catalogue while holding the handler's write
+// lock, allowing the catalogue to be safely modified while it is being served.
+func (h *Handler) Update(fn func(cat *Hypercat) error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.attachACL()
+
+	return fn(h.cat)
+}
+
+// attachACL attaches the handler's ACL to the served catalogue as an Index,
+// unless it is already attached, so that its entries follow items whose hrefs
+// are rewritten. It must be called with the write lock held.
+func (h *Handler) attachACL() {
+	if h.ACL == nil {
+		return
+	}
+
+	for _, idx := range h.cat.Indexes {
+		if idx == Index(h.ACL) {
+			return
+		}
+	}
+
+	h.cat.AddIndex(h.ACL)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	op, ok := operationFor(r)
+	if !ok {
+		w.Header().Set("Allow", "GET, HEAD, POST, PUT, DELETE")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	r, ok = h.authorize(w, r, op)
+	if !ok {
+		return
+	}
+
+	switch {
+	case op == ReadOperation && r.Method == "POST":
+		h.serveMultiSearch(w, r)
+	case op == ReadOperation:
+		h.serveCatalogue(w, r, func(cat *Hypercat) (*Hypercat, error) {
+			return h.search(r, cat, r.URL.Query())
+		})
+	case r.Method == "POST":
+		h.serveWrite(w, r, http.StatusCreated, func(cat *Hypercat, item *Item) error {
+			if !h.visible(r, item.Href) {
+				return ErrHrefForbidden
+			}
+
+			return cat.AddItem(item)
+		})
+	case r.Method == "PUT":
+		h.serveWrite(w, r, http.StatusOK, func(cat *Hypercat, item *Item) error {
+			if item.Href != r.URL.Query().Get("href") {
+				return ErrHrefMismatch
+			}
+
+			if !h.visible(r, item.Href) {
+				return ErrHrefNotFound
+			}
+
+			return cat.ReplaceItem(item)
+		})
+	case r.Method == "DELETE":
+		h.serveUpdate(w, r, http.StatusNoContent, func(cat *Hypercat) error {
+			href := r.URL.Query().Get("href")
+			if href == "" {
+				return ErrMissingHref
+			}
+
+			if !h.visible(r, href) {
+				return ErrHrefNotFound
+			}
+
+			return cat.RemoveItem(href)
+		})
+	}
+}
+
+// operationFor returns the catalogue operation performed by the request, and
+// whether its method is supported.
+func operationFor(r *http.Request) (Operation, bool) {
+	switch r.Method {
+	case "GET", "HEAD":
+		return ReadOperation, true
+	case "POST":
+		if _, ok := r.URL.Query()["multi"]; ok {
+			return ReadOperation, true
+		}
+
+		return AddOperation, true
+	case "PUT":
+		return ReplaceOperation, true
+	case "DELETE":
+		return DeleteOperation, true
+	default:
+		return 0, false
+	}
+}
+
+// authorize authenticates the request and checks that its principal may
+// perform the given operation, returning the request with the principal
+// stored in its context. Otherwise an error response is written and false is
+// returned.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, op Operation) (*http.Request, bool) {
+	principal := ""
+
+	if h.Authenticator != nil {
+		var err error
+
+		principal, err = h.Authenticator.Authenticate(r)
+		if err != nil {
+			h.unauthorized(w, err.Error())
+			return r, false
+		}
+	}
+
+	if h.Authorizer != nil && !h.Authorizer(principal, op) {
+		if principal == "" {
+			h.unauthorized(w, http.StatusText(http.StatusUnauthorized))
+		} else {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+
+		return r, false
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)), true
+}
+
+// catalogueFor returns the catalogue as seen by the principal making the
+// request, advertising the handler's search modes. It must be called with the
+// read or write lock held.
+func (h *Handler) catalogueFor(r *http.Request) *Hypercat {
+	return h.viewOf(r, h.cat)
+}
+
+// viewOf returns the served catalogue, or the results of searching it, as seen
+// by the principal making the request, advertising the handler's search
+// modes. It must be called with the read or write lock held.
+func (h *Handler) viewOf(r *http.Request, cat *Hypercat) *Hypercat {
+	if h.ACL != nil {
+		cat = h.ACL.View(cat, PrincipalFromContext(r.Context()))
+	}
+
+	if len(h.SearchModes) > 0 && !sameModes(cat.Vals(SupportsSearchRel), h.SearchModes) {
+		if cat == h.cat {
+			cat = cat.withItems(cat.Items)
+		}
+
+		cat.Metadata.Remove(SupportsSearchRel)
+
+		for _, mode := range h.SearchModes {
+			cat.Metadata.Add(SupportsSearchRel, mode)
+		}
+	}
+
+	return cat
+}
+
+// sameModes reports whether two lists contain the same search modes, ignoring
+// their order and any repetition.
+func sameModes(a, b []string) bool {
+	set := func(modes []string) map[string]bool {
+		m := map[string]bool{}
+
+		for _, mode := range modes {
+			m[mode] = true
+		}
+
+		return m
+	}
+
+	return reflect.DeepEqual(set(a), set(b))
+}
+
+// supports reports whether the handler answers searches using the given mode.
+func (h *Handler) supports(mode string) bool {
+	for _, supported := range h.SearchModes {
+		if supported == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// search returns the items of the served catalogue matching the search
+// expressed by the query parameters, as seen by the principal making the
+// request, or cat, the catalogue as they see it, if the query contains no
+// search parameters or the handler has no search modes. Searches are made on
+// the served catalogue itself, so that they are answered by its indexes.
+func (h *Handler) search(r *http.Request, cat *Hypercat, query url.Values) (*Hypercat, error) {
+	if len(h.SearchModes) == 0 {
+		return cat, nil
+	}
+
+	mode, err := searchMode(query)
+	if err != nil || mode == "" {
+		return cat, err
+	}
+
+	if !h.supports(mode) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSearch, mode)
+	}
+
+	results, err := h.cat.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.viewOf(r, results), nil
+}
+
+// serveMultiSearch answers a multi-search request, whose body is a
+// MultiSearch.
+func (h *Handler) serveMultiSearch(w http.ResponseWriter, r *http.Request) {
+	if !h.supports(MultiSearchVal) {
+		http.Error(w, fmt.Sprintf("%v: %s", ErrUnsupportedSearch, MultiSearchVal), http.StatusBadRequest)
+		return
+	}
+
+	multi := &MultiSearch{}
+
+	err := json.NewDecoder(r.Body).Decode(multi)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, raw := range multi.Queries {
+		query, err := url.ParseQuery(strings.TrimPrefix(raw, "?"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%v: %v", ErrInvalidSearch, err), http.StatusBadRequest)
+			return
+		}
+
+		mode, _ := searchMode(query)
+		if mode != "" && !h.supports(mode) {
+			http.Error(w, fmt.Sprintf("%v: %s", ErrUnsupportedSearch, mode), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.serveCatalogue(w, r, func(cat *Hypercat) (*Hypercat, error) {
+		results, err := h.cat.MultiSearch(multi)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.viewOf(r, results), nil
+	})
+}
+
+// ServeFeed streams the served catalogue's Feed to the client, as described
+// by Feed.ServeHTTP, after authenticating and authorizing the request as a
+// read. If an ACL is attached, events for items hidden from the principal are
+// omitted. Responds with 404 Not Found if the catalogue has no Feed.
+func (h *Handler) ServeFeed(w http.ResponseWriter, r *http.Request) {
+	r, ok := h.authorize(w, r, ReadOperation)
+	if !ok {
+		return
+	}
+
+	h.mu.RLock()
+	feed := h.cat.Feed
+	h.mu.RUnlock()
+
+	if feed == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	feed.serve(w, r, func(ev Event) bool {
+		return ev.Href == "" || h.visible(r, ev.Href)
+	})
+}
+
+// visible reports whether the item with the given href is visible to the
+// principal making the request.
+func (h *Handler) visible(r *http.Request, href string) bool {
+	return h.ACL == nil || h.ACL.Visible(href, PrincipalFromContext(r.Context()))
+}
+
+// unauthorized writes a 401 response, challenging the client for the
+// credentials accepted by the Authenticator.
+func (h *Handler) unauthorized(w http.ResponseWriter, msg string) {
+	if c, ok := h.Authenticator.(Challenger); ok {
+		w.Header().Set("WWW-Authenticate", c.Challenge())
+	}
+
+	http.Error(w, msg, http.StatusUnauthorized)
+}
+
+// serveCatalogue writes the catalogue returned by find to the response, or a
+// 304 response if the request's conditional headers show the client's copy is
+// current. Find is called with the catalogue as seen by the request's
+// principal, and returns either that catalogue or the results of searching it.
+func (h *Handler) serveCatalogue(w http.ResponseWriter, r *http.Request, find func(cat *Hypercat) (*Hypercat, error)) {
+	h.mu.RLock()
+	cat := h.catalogueFor(r)
+
+	results, err := find(cat)
+	if err != nil {
+		h.mu.RUnlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := pageFor(results, r)
+	if err != nil {
+		h.mu.RUnlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := json.Marshal(page)
+	etag, _ := cat.ETag()
+	modified, modErr := h.cat.LastUpdated()
+	h.mu.RUnlock()
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+
+	if h.ACL != nil {
+		w.Header().Set("Vary", "Authorization")
+	}
+
+	if modErr == nil {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, modified, modErr == nil) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", HypercatMediaType)
+	w.Write(body)
+}
+
+// pageFor returns the page of the catalogue selected by the request's
+// pagination parameters, or the catalogue itself if there are none. Pages
+// other than the last carry a NextPageRel linking to the following page.
+func pageFor(cat *Hypercat, r *http.Request) (*Hypercat, error) {
+	query := r.URL.Query()
+
+	_, useCursor := query["cursor"]
+
+	if query.Get("limit") == "" && query.Get("offset") == "" && !useCursor {
+		return cat, nil
+	}
+
+	limit, err := queryInt(query.Get("limit"))
+	if err != nil {
+		return nil, errors.New("invalid limit")
+	}
+
+	var page *Hypercat
+
+	if useCursor {
+		var next string
+
+		page, next, err = cat.PaginateCursor(query.Get("cursor"), limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if next == "" {
+			return page, nil
+		}
+
+		query.Set("cursor", next)
+	} else {
+		offset, err := queryInt(query.Get("offset"))
+		if err != nil {
+			return nil, errors.New("invalid offset")
+		}
+
+		var next int
+
+		page, next = cat.Paginate(offset, limit)
+		if next == -1 {
+			return page, nil
+		}
+
+		query.Set("offset", strconv.Itoa(next))
+	}
+
+	link := *r.URL
+	link.RawQuery = query.Encode()
+	page.AddRel(NextPageRel, link.RequestURI())
+
+	return page, nil
+}
+
+// queryInt parses a non negative integer query parameter, treating an empty
+// value as 0.
+func queryInt(val string) (int, error) {
+	if val == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil || i < 0 {
+		return 0, strconv.ErrSyntax
+	}
+
+	return i, nil
+}
+
+// serveWrite decodes an item from the request body and applies the given
+// operation to the catalogue, provided any If-Match precondition holds.
+func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, status int, op func(*Hypercat, *Item) error) {
+	item := &Item{}
+
+	err := json.NewDecoder(r.Body).Decode(item)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.serveUpdate(w, r, status, func(cat *Hypercat) error {
+		return op(cat, item)
+	})
+}
+
+// serveUpdate applies the given operation to the catalogue, provided any
+// If-Match precondition holds, responding with the given status on success.
+func (h *Handler) serveUpdate(w http.ResponseWriter, r *http.Request, status int, op func(*Hypercat) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.preconditionHolds(r) {
+		http.Error(w, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		return
+	}
+
+	err := op(h.cat)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+
+	etag, err := h.catalogueFor(r).ETag()
+	if err == nil {
+		w.Header().Set("ETag", etag)
+	}
+
+	w.WriteHeader(status)
+}
+
+// preconditionHolds reports whether the request's If-Match header, if any,
+// matches the current catalogue as seen by the principal making the request.
+// It must be called with the write lock held.
+func (h *Handler) preconditionHolds(r *http.Request) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+
+	etag, err := h.catalogueFor(r).ETag()
+	if err != nil {
+		return false
+	}
+
+	return etagMatches(header, etag, false)
+}
+
+// notModified reports whether a GET request's conditional headers show that
+// the client already holds the current representation. If-Modified-Since is
+// only considered in the absence of If-None-Match.
+func notModified(r *http.Request, etag string, modified time.Time, hasModified bool) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etagMatches(header, etag, true)
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && hasModified {
+		since, err := http.ParseTime(header)
+		if err == nil && !modified.Truncate(time.Second).After(since) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// etagMatches reports whether the given entity tag is matched by an
+// If-Match or If-None-Match header value. Weak comparison ignores the weakness
+// indicator, whereas strong comparison never matches weak tags.
+func etagMatches(header, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+
+			candidate = candidate[2:]
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statusFor returns the HTTP status code used to report the given error from a
+// catalogue operation.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrDuplicateHref):
+		return http.StatusConflict
+	case errors.Is(err, ErrHrefNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrHrefForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
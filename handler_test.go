@@ -0,0 +1,390 @@
+package hypercat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testHandler returns a handler serving a catalogue containing a single item.
+func testHandler(t *testing.T) *Handler {
+	cat := NewHypercat("Catalogue description")
+	cat.Clock = testClock(time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	err := cat.AddItem(NewItem("/foo", "Item description"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return NewHandler(cat)
+}
+
+func serve(handler http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	for key, val := range headers {
+		req.Header.Set(key, val)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestHandlerGet(t *testing.T) {
+	handler := testHandler(t)
+
+	w := serve(handler, "GET", "/cat", "", nil)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != HypercatMediaType {
+		t.Errorf("Handler content type error, got '%v'", w.Header().Get("Content-Type"))
+	}
+
+	etag, _ := handler.cat.ETag()
+
+	if w.Header().Get("ETag") != etag {
+		t.Errorf("Handler ETag error, expected '%v', got '%v'", etag, w.Header().Get("ETag"))
+	}
+
+	if w.Header().Get("Last-Modified") != "Tue, 01 Mar 2016 12:00:00 GMT" {
+		t.Errorf("Handler Last-Modified error, got '%v'", w.Header().Get("Last-Modified"))
+	}
+
+	cat, err := Parse(w.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cat.Items) != 1 {
+		t.Errorf("Handler body error, expected 1 item, got '%v'", len(cat.Items))
+	}
+}
+
+func TestHandlerConditionalGet(t *testing.T) {
+	handler := testHandler(t)
+	etag, _ := handler.cat.ETag()
+
+	var testcases = []struct {
+		headers  map[string]string
+		expected int
+	}{
+		{map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{map[string]string{"If-Modified-Since": "Tue, 01 Mar 2016 12:00:00 GMT"}, http.StatusNotModified},
+		{map[string]string{"If-Modified-Since": "Tue, 01 Mar 2016 11:59:59 GMT"}, http.StatusOK},
+		{map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Tue, 01 Mar 2016 12:00:00 GMT"}, http.StatusOK},
+	}
+
+	for _, testcase := range testcases {
+		w := serve(handler, "GET", "/cat", "", testcase.headers)
+
+		if w.Code != testcase.expected {
+			t.Errorf("Handler status error for headers '%v', expected '%v', got '%v'", testcase.headers, testcase.expected, w.Code)
+		}
+
+		if w.Code == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("Handler should not send a body with a 304 response")
+		}
+	}
+}
+
+func TestHandlerPost(t *testing.T) {
+	handler := testHandler(t)
+	body := `{"href":"/bar","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Bar"}]}`
+
+	w := serve(handler, "POST", "/cat", body, nil)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusCreated, w.Code)
+	}
+
+	if len(handler.cat.Items) != 2 {
+		t.Errorf("Handler should have added an item")
+	}
+
+	w = serve(handler, "POST", "/cat", body, nil)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusConflict, w.Code)
+	}
+
+	w = serve(handler, "POST", "/cat", `{"href":"/baz"}`, nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHandlerPut(t *testing.T) {
+	handler := testHandler(t)
+	etag, _ := handler.cat.ETag()
+	body := `{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"New"}]}`
+
+	w := serve(handler, "PUT", "/cat?href=/foo", body, map[string]string{"If-Match": `"stale"`})
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusPreconditionFailed, w.Code)
+	}
+
+	w = serve(handler, "PUT", "/cat?href=/foo", body, map[string]string{"If-Match": etag})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusOK, w.Code)
+	}
+
+	if handler.cat.Items[0].Description != "New" {
+		t.Errorf("Handler should have replaced the item")
+	}
+
+	newETag, _ := handler.cat.ETag()
+
+	if newETag == etag || w.Header().Get("ETag") != newETag {
+		t.Errorf("Handler ETag error, expected '%v', got '%v'", newETag, w.Header().Get("ETag"))
+	}
+
+	w = serve(handler, "PUT", "/cat?href=/foo", body, map[string]string{"If-Match": etag})
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusPreconditionFailed, w.Code)
+	}
+
+	w = serve(handler, "PUT", "/cat?href=/bar", body, nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusBadRequest, w.Code)
+	}
+
+	body = `{"href":"/bar","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Bar"}]}`
+	w = serve(handler, "PUT", "/cat?href=/bar", body, nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandlerDelete(t *testing.T) {
+	handler := testHandler(t)
+	etag, _ := handler.cat.ETag()
+
+	w := serve(handler, "DELETE", "/cat?href=/foo", "", map[string]string{"If-Match": `"stale"`})
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusPreconditionFailed, w.Code)
+	}
+
+	w = serve(handler, "DELETE", "/cat?href=/foo", "", map[string]string{"If-Match": etag})
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNoContent, w.Code)
+	}
+
+	if len(handler.cat.Items) != 0 {
+		t.Errorf("Handler should have removed the item")
+	}
+
+	w = serve(handler, "DELETE", "/cat?href=/foo", "", nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusNotFound, w.Code)
+	}
+
+	w = serve(handler, "DELETE", "/cat", "", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	w := serve(testHandler(t), "PATCH", "/cat", "", nil)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Handler status error, expected '%v', got '%v'", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestHandlerPagination(t *testing.T) {
+	handler := NewHandler(testCatalogue(3))
+
+	var testcases = []struct {
+		target string
+		status int
+		items  int
+		next   string
+	}{
+		{"/cat", http.StatusOK, 3, ""},
+		{"/cat?limit=2", http.StatusOK, 2, "/cat?limit=2&offset=2"},
+		{"/cat?limit=2&offset=2", http.StatusOK, 1, ""},
+		{"/cat?limit=-1", http.StatusBadRequest, 0, ""},
+		{"/cat?offset=foo", http.StatusBadRequest, 0, ""},
+		{"/cat?cursor=!!", http.StatusBadRequest, 0, ""},
+	}
+
+	for _, testcase := range testcases {
+		w := serve(handler, "GET", testcase.target, "", nil)
+
+		if w.Code != testcase.status {
+			t.Errorf("Handler status error for '%v', expected '%v', got '%v'", testcase.target, testcase.status, w.Code)
+			continue
+		}
+
+		if w.Code != http.StatusOK {
+			continue
+		}
+
+		page, err := Parse(w.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(page.Items) != testcase.items {
+			t.Errorf("Handler pagination error for '%v', expected '%v' items, got '%v'", testcase.target, testcase.items, len(page.Items))
+		}
+
+		next := ""
+		if vals := page.Vals(NextPageRel); len(vals) > 0 {
+			next = vals[0]
+		}
+
+		if next != testcase.next {
+			t.Errorf("Handler pagination error for '%v', expected next '%v', got '%v'", testcase.target, testcase.next, next)
+		}
+	}
+}
+
+func TestHandlerSearch(t *testing.T) {
+	handler := NewHandler(searchCatalogue())
+	handler.SearchModes = []string{SimpleSearchVal, PrefixSearchVal, MultiSearchVal}
+
+	var testcases = []struct {
+		method   string
+		target   string
+		body     string
+		status   int
+		expected []string
+	}{
+		{"GET", "/cat", "", http.StatusOK, []string{"/sensors/london", "/sensors/paris", "/parks"}},
+		{"GET", "/cat?val=banana", "", http.StatusOK, []string{"/sensors/paris"}},
+		{"GET", "/cat?prefix-href=/sensors/&limit=1", "", http.StatusOK, []string{"/sensors/london"}},
+		{"GET", "/cat?lexrange-rel=urn:example:name", "", http.StatusBadRequest, nil},
+		{"GET", "/cat?href=/parks&prefix-href=/", "", http.StatusBadRequest, nil},
+		{"POST", "/cat?multi", `{"query":["val=apple","val=cherry"]}`, http.StatusOK, []string{"/sensors/london", "/parks"}},
+		{"POST", "/cat?multi", `{"query":["val=apple","lexrange-rel=urn:example:name"]}`, http.StatusBadRequest, nil},
+		{"POST", "/cat?multi", `{"query":`, http.StatusBadRequest, nil},
+		{"POST", "/cat?multi", `{"query":["val=apple","val=%zz"]}`, http.StatusBadRequest, nil},
+	}
+
+	for _, testcase := range testcases {
+		w := serve(handler, testcase.method, testcase.target, testcase.body, nil)
+
+		if w.Code != testcase.status {
+			t.Errorf("Handler status error for '%v', expected '%v', got '%v'", testcase.target, testcase.status, w.Code)
+			continue
+		}
+
+		if testcase.expected == nil {
+			continue
+		}
+
+		cat, err := Parse(w.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := hrefs(cat.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("Handler search error for '%v', expected '%v', got '%v'", testcase.target, testcase.expected, got)
+		}
+
+		if modes := cat.Vals(SupportsSearchRel); !reflect.DeepEqual(modes, handler.SearchModes) {
+			t.Errorf("Handler search modes error, expected '%v', got '%v'", handler.SearchModes, modes)
+		}
+	}
+
+	if len(handler.cat.Vals(SupportsSearchRel)) != 0 {
+		t.Errorf("Handler should not advertise search modes within the served catalogue")
+	}
+
+	handler.SearchModes = nil
+
+	w := serve(handler, "GET", "/cat?val=banana", "", nil)
+	cat, _ := Parse(w.Body)
+
+	if len(cat.Items) != 3 || len(cat.Vals(SupportsSearchRel)) != 0 {
+		t.Errorf("Handler should ignore search parameters without search modes")
+	}
+}
+
+func TestHandlerSearchIndex(t *testing.T) {
+	cat := searchCatalogue()
+	cat.AddIndex(NewSpatialIndex(0))
+
+	handler := NewHandler(cat)
+	handler.SearchModes = []string{GeoBoundSearchVal}
+	handler.ACL = NewACL()
+	handler.ACL.Restrict("/sensors/paris", "partner")
+
+	// Moving an item directly leaves the index stale, revealing which of the
+	// index and a scan of the items answered the search.
+	cat.Items[0].SetFloat(LatitudeRel, 0)
+
+	target := "/cat?geobound-minlong=-1&geobound-minlat=40&geobound-maxlong=3&geobound-maxlat=52"
+
+	var testcases = []struct {
+		headers  map[string]string
+		expected []string
+	}{
+		{nil, []string{"/sensors/london"}},
+		{map[string]string{"Authorization": "Bearer partner-token"}, []string{"/sensors/london", "/sensors/paris"}},
+	}
+
+	handler.Authenticator = BearerTokens(map[string]string{"partner-token": "partner"})
+
+	for _, testcase := range testcases {
+		w := serve(handler, "GET", target, "", testcase.headers)
+
+		results, err := Parse(w.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := hrefs(results.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("Handler indexed search error, expected '%v', got '%v'", testcase.expected, got)
+		}
+
+		if modes := results.Vals(SupportsSearchRel); !reflect.DeepEqual(modes, handler.SearchModes) {
+			t.Errorf("Handler search modes error, expected '%v', got '%v'", handler.SearchModes, modes)
+		}
+	}
+}
+
+func TestHandlerSearchSigned(t *testing.T) {
+	key := testKeys(t)[0]
+
+	cat := searchCatalogue()
+	cat.AddRel(SupportsSearchRel, PrefixSearchVal)
+	cat.AddRel(SupportsSearchRel, SimpleSearchVal)
+
+	err := cat.Sign(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	handler := NewHandler(cat)
+	handler.SearchModes = []string{SimpleSearchVal, PrefixSearchVal}
+
+	w := serve(handler, "GET", "/cat", "", nil)
+
+	_, err = Parse(w.Body, VerifySignature(key.Public()))
+	if err != nil {
+		t.Errorf("Handler should serve a signed catalogue advertising its search modes unchanged, got '%v'", err)
+	}
+}
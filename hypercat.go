@@ -1,17 +1,31 @@
 package hypercat
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"io"
+	"net/url"
+	"time"
 )
 
 // Hypercat is the representation of the Hypercat catalogue object, which is
 // the parent element of each catalogue instance.
 type Hypercat struct {
-	Items       Items    `json:"items"`
-	Metadata    Metadata `json:"catalogue-metadata"`
-	Description string   `json:"-"` // Hypercat spec is fuzzy about whether there can be more than one description. We assume not.
-	ContentType string   `json:"-"`
+	Items        Items                      `json:"items"`
+	Metadata     Metadata                   `json:"catalogue-metadata"`
+	Description  string                     `json:"-"` // Hypercat spec is fuzzy about whether there can be more than one description. We assume one per language.
+	Descriptions map[string]string          `json:"-"` // Descriptions in languages other than DefaultLanguage, keyed by language tag.
+	Extra        map[string]json.RawMessage `json:"-"` // Unknown JSON fields, preserved when the catalogue is marshalled.
+	ContentType  string                     `json:"-"`
+	Feed         *Feed                      `json:"-"` // Optional change feed receiving every mutation made through the catalogue API.
+	BaseURL      *url.URL                   `json:"-"` // Optional URL of the catalogue, against which relative item hrefs are resolved.
+	Indexes      []Index                    `json:"-"` // Indexes updated with every mutation made through the catalogue API, attached with AddIndex.
+
+	// Clock is an optional source of the current time. If set, the
+	// LastUpdatedRel of the catalogue and of any affected item is maintained
+	// whenever they are modified through the catalogue API. Signed items keep
+	// the timestamp they were signed with.
+	Clock func() time.Time `json:"-"`
 }
 
 // NewHypercat is a constructor function that creates and returns a Hypercat
@@ -28,50 +42,90 @@ func NewHypercat(description string) *Hypercat {
 	}
 }
 
-// Parse is a function that takes as input an io.Reader instance, which must
-// return a valid Hypercat document when read. This function then passes this
-// reader to a JSON Decoder, which attempts to parse and instantiate a valid
-// Hypercat struct.
-func Parse(r io.Reader) (*Hypercat, error) {
-	cat := Hypercat{}
-	err := json.NewDecoder(r).Decode(&cat)
+// AddRel is a function for adding a Rel object to a catalogue. This may result
+// in duplicated Rel keys as this is permitted by the Hypercat spec.
+func (h *Hypercat) AddRel(rel, val string) {
+	h.Metadata.Add(rel, val)
+	h.touchRel(rel)
 
-	if err != nil {
-		return nil, err
+	h.publish(Event{Type: RelAdded, Rel: NewRel(rel, val)})
+}
+
+// ReplaceRel is a function that attempts to replace the value of every Rel
+// object with the given key attached to this Catalogue. Returns false, having
+// no effect, if the Rel key isn't found.
+func (h *Hypercat) ReplaceRel(rel, val string) bool {
+	if !h.Metadata.Replace(rel, val) {
+		return false
 	}
 
-	return &cat, nil
+	h.touchRel(rel)
+	h.publish(Event{Type: RelReplaced, Rel: NewRel(rel, val)})
+
+	return true
 }
 
-// AddRel is a function for adding a Rel object to a catalogue. This may result
-// in duplicated Rel keys as this is permitted by the Hypercat spec.
-// TODO: this code is duplicated in item
-func (h *Hypercat) AddRel(rel, val string) {
-	h.Metadata = append(h.Metadata, Rel{Rel: rel, Val: val})
+// SetRel sets the value of a Rel attached to this Catalogue, replacing any
+// existing values or adding the Rel if it isn't found.
+func (h *Hypercat) SetRel(rel, val string) {
+	typ := RelReplaced
+
+	if !h.Metadata.Has(rel) {
+		typ = RelAdded
+	}
+
+	h.Metadata.Set(rel, val)
+	h.touchRel(rel)
+
+	h.publish(Event{Type: typ, Rel: NewRel(rel, val)})
 }
 
-// ReplaceRel is a function that attempts to replace the value of a specific
-// Rel object if it is attached to this Catalogue. If the Rel key isn't found
-// this will have no effect.
-func (h *Hypercat) ReplaceRel(rel, val string) {
-	for i, relationship := range h.Metadata {
-		if relationship.Rel == rel {
-			h.Metadata[i] = Rel{Rel: rel, Val: val}
-		}
+// RemoveRel removes every Rel with the given key from this Catalogue,
+// returning the number of Rels removed.
+func (h *Hypercat) RemoveRel(rel string) int {
+	vals := h.Metadata.Vals(rel)
+
+	removed := h.Metadata.Remove(rel)
+	if removed > 0 {
+		h.touchRel(rel)
+	}
+
+	for _, val := range vals {
+		h.publish(Event{Type: RelRemoved, Rel: NewRel(rel, val)})
 	}
+
+	return removed
+}
+
+// RemoveRelVal removes every Rel with the given key and value from this
+// Catalogue, returning the number of Rels removed.
+func (h *Hypercat) RemoveRelVal(rel, val string) int {
+	removed := h.Metadata.RemoveVal(rel, val)
+	if removed > 0 {
+		h.touchRel(rel)
+	}
+
+	for i := 0; i < removed; i++ {
+		h.publish(Event{Type: RelRemoved, Rel: NewRel(rel, val)})
+	}
+
+	return removed
+}
+
+// HasRel reports whether a Rel with the given key is attached to this
+// Catalogue.
+func (h *Hypercat) HasRel(rel string) bool {
+	return h.Metadata.Has(rel)
 }
 
 // AddItem is a function for adding an Item to a catalogue. Returns an error if
 // we try to add an Item whose href is already defined within the catalogue.
 func (h *Hypercat) AddItem(item *Item) error {
-	for _, i := range h.Items {
-		if item.Href == i.Href {
-			err := ErrDuplicateHref
-			return err
-		}
+	if h.indexOf(item.Href) != -1 {
+		return ErrDuplicateHref
 	}
 
-	h.Items = append(h.Items, *item)
+	h.storeItem(-1, item)
 
 	return nil
 }
@@ -80,95 +134,140 @@ func (h *Hypercat) AddItem(item *Item) error {
 // an error if we try to replace an Item that isn't defined within the
 // catalogue.
 func (h *Hypercat) ReplaceItem(newItem *Item) error {
-	for index, oldItem := range h.Items {
-		if newItem.Href == oldItem.Href {
-			h.Items[index] = *newItem
-			return nil
-		}
+	index := h.indexOf(newItem.Href)
+	if index == -1 {
+		return ErrHrefNotFound
 	}
 
-	return ErrHrefNotFound
+	h.storeItem(index, newItem)
+
+	return nil
 }
 
-// MarshalJSON returns the JSON encoding of a Hypercat. This function is the
-// implementation of the Marshaler interface.
-func (h *Hypercat) MarshalJSON() ([]byte, error) {
-	metadata := h.Metadata
+// UpsertItem is a function for adding an Item to a catalogue, replacing any
+// existing item with the same href. Returns true if the item was added, or
+// false if it replaced an existing item.
+func (h *Hypercat) UpsertItem(item *Item) bool {
+	index := h.indexOf(item.Href)
 
-	if h.Description != "" {
-		metadata = append(metadata, Rel{Rel: DescriptionRel, Val: h.Description})
-	}
+	h.storeItem(index, item)
 
-	if h.ContentType != "" {
-		metadata = append(metadata, Rel{Rel: ContentTypeRel, Val: h.ContentType})
+	return index == -1
+}
+
+// RemoveItem is a function for removing an item from a catalogue. Returns an
+// error if we try to remove an Item that isn't defined within the catalogue.
+func (h *Hypercat) RemoveItem(href string) error {
+	index := h.indexOf(href)
+	if index == -1 {
+		return ErrHrefNotFound
 	}
 
-	return json.Marshal(struct {
-		Items    []Item   `json:"items"`
-		Metadata Metadata `json:"catalogue-metadata"`
-	}{
-		Items:    h.Items,
-		Metadata: metadata,
-	})
+	last := len(h.Items) - 1
+
+	copy(h.Items[index:], h.Items[index+1:])
+
+	// Clear the vacated element so the removed item can be collected.
+	h.Items[last] = Item{}
+	h.Items = h.Items[:last]
+	h.touch(nil)
+
+	h.publish(Event{Type: ItemRemoved, Href: href})
+
+	return nil
 }
 
-// UnmarshalJSON is the required function for structs that implement the
-// Unmarshaler interface.
-func (h *Hypercat) UnmarshalJSON(b []byte) error {
-	type tempCat struct {
-		Items    Items    `json:"items"`
-		Metadata Metadata `json:"catalogue-metadata"`
+// indexOf returns the index of the item with the given href, or -1 if there
+// is no such item within the catalogue.
+func (h *Hypercat) indexOf(href string) int {
+	for i := range h.Items {
+		if h.Items[i].Href == href {
+			return i
+		}
 	}
 
-	t := tempCat{}
+	return -1
+}
 
-	err := json.Unmarshal(b, &t)
+// storeItem stores a copy of the given item at index within the catalogue's
+// items, or appends it if index is -1, and publishes the corresponding event.
+func (h *Hypercat) storeItem(index int, item *Item) {
+	stored := item.clone()
+	h.touch(stored)
 
-	if err != nil {
-		return err
-	}
+	if index == -1 {
+		h.Items = append(h.Items, *stored)
+		h.publish(Event{Type: ItemAdded, Href: item.Href, Item: stored.clone()})
 
-	for _, rel := range t.Metadata {
-		if rel.Rel == DescriptionRel {
-			h.Description = rel.Val
-		} else if rel.Rel == ContentTypeRel {
-			h.ContentType = rel.Val
-		} else {
-			h.Metadata = append(h.Metadata, rel)
-		}
+		return
 	}
 
-	if h.Description == "" {
-		return ErrMissingDescriptionRel
+	h.Items[index] = *stored
+	h.publish(Event{Type: ItemReplaced, Href: item.Href, Item: stored.clone()})
+}
+
+// publish applies an event describing a mutation to the catalogue's indexes,
+// and sends it to the catalogue's Feed if one is attached.
+func (h *Hypercat) publish(ev Event) {
+	for _, idx := range h.Indexes {
+		idx.Apply(ev)
 	}
 
-	if h.ContentType == "" {
-		return ErrMissingContentTypeRel
+	if h.Feed != nil {
+		h.Feed.publish(ev)
 	}
+}
 
-	return nil
+// MarshalJSON returns the JSON encoding of a Hypercat. This function is the
+// implementation of the Marshaler interface.
+func (h *Hypercat) MarshalJSON() ([]byte, error) {
+	return marshalObject([]jsonField{
+		{key: "items", value: h.Items},
+		{key: "catalogue-metadata", value: h.allMetadata()},
+	}, h.Extra, false)
 }
 
-// Rels returns a slice containing all the Rel values of catalogue's metadata.
-func (h *Hypercat) Rels() []string {
-	rels := make([]string, len(h.Metadata))
+// allMetadata returns a copy of the catalogue's metadata including the Rels
+// stored in dedicated fields, as they appear when serialized.
+func (h *Hypercat) allMetadata() Metadata {
+	metadata := append(Metadata{}, h.Metadata...)
+
+	metadata = append(metadata, descriptionMetadata(h.Description, h.Descriptions)...)
 
-	for i, rel := range h.Metadata {
-		rels[i] = rel.Rel
+	if h.ContentType != "" {
+		metadata = append(metadata, Rel{Rel: ContentTypeRel, Val: h.ContentType})
 	}
 
-	return rels
+	return metadata
 }
 
-// Vals returns a slice of all values that match the given rel value.
-func (h *Hypercat) Vals(key string) []string {
-	vals := []string{}
-
-	for _, rel := range h.Metadata {
-		if rel.Rel == key {
-			vals = append(vals, rel.Val)
-		}
+// ETag returns a strong entity tag for the catalogue, computed from a hash of
+// its canonical JSON serialization, so semantically identical catalogues share
+// the same tag. The returned value is quoted, ready for use in an ETag header.
+func (h *Hypercat) ETag() (string, error) {
+	b, err := h.CanonicalJSON()
+	if err != nil {
+		return "", err
 	}
 
-	return vals
+	sum := sha256.Sum256(b)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
+
+// UnmarshalJSON is the required function for structs that implement the
+// Unmarshaler interface. Failures are reported as a *ParseError recording
+// their position within the document.
+func (h *Hypercat) UnmarshalJSON(b []byte) error {
+	return h.decode(b, &parseOptions{})
+}
+
+// Rels returns a slice containing all the Rel values of catalogue's metadata.
+func (h *Hypercat) Rels() []string {
+	return h.Metadata.Rels()
+}
+
+// Vals returns a slice of all values that match the given rel value.
+func (h *Hypercat) Vals(key string) []string {
+	return h.Metadata.Vals(key)
 }
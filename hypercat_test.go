@@ -67,6 +67,28 @@ func TestReplaceRelOnCatalogue(t *testing.T) {
 	}
 }
 
+func TestRemoveRelFromCatalogue(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+	cat.AddRel("relation1", "value1")
+	cat.AddRel("relation2", "value2")
+	cat.AddRel("relation1", "value3")
+	cat.Feed = NewFeed(0)
+
+	if cat.RemoveRelVal("relation1", "value3") != 1 || cat.RemoveRel("relation1") != 1 {
+		t.Errorf("Catalogue rels not removed, got '%v'", cat.Metadata)
+	}
+
+	if cat.HasRel("relation1") || !cat.HasRel("relation2") {
+		t.Errorf("Catalogue rel removal error, got '%v'", cat.Metadata)
+	}
+
+	events, _ := cat.Feed.Since(0)
+
+	if len(events) != 2 || events[0].Type != RelRemoved || events[1].Rel.Val != "value1" {
+		t.Errorf("Catalogue rel removal should publish an event per value, got '%v'", events)
+	}
+}
+
 func TestAddItem(t *testing.T) {
 	cat := NewHypercat("Catalogue description")
 	item := NewItem("/foo", "Item description")
@@ -141,6 +163,44 @@ func TestReplacingMissingItem(t *testing.T) {
 	}
 }
 
+func TestUpsertItem(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+
+	if !cat.UpsertItem(NewItem("/foo", "Item1 description")) {
+		t.Errorf("Upserting a new item should have added it")
+	}
+
+	if cat.UpsertItem(NewItem("/foo", "Item2 description")) {
+		t.Errorf("Upserting an existing item should have replaced it")
+	}
+
+	if len(cat.Items) != 1 || cat.Items[0].Description != "Item2 description" {
+		t.Errorf("Item not replaced, got '%v'", cat.Items)
+	}
+}
+
+func TestRemoveItem(t *testing.T) {
+	cat := testCatalogue(3)
+
+	err := cat.RemoveItem("/1")
+	if err != nil {
+		t.Errorf("Error removing item from catalogue: %v", err)
+	}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, []string{"/0", "/2"}) {
+		t.Errorf("RemoveItem error, expected '[/0 /2]', got '%v'", got)
+	}
+
+	if tail := cat.Items[:3][2]; tail.Href != "" || tail.Metadata != nil {
+		t.Errorf("RemoveItem should clear the vacated element, got '%v'", tail)
+	}
+
+	err = cat.RemoveItem("/1")
+	if err != ErrHrefNotFound {
+		t.Errorf("RemoveItem error, expected '%v', got '%v'", ErrHrefNotFound, err)
+	}
+}
+
 func TestHypercatMarshalling(t *testing.T) {
 	item := NewItem("/cat", "Item description")
 
@@ -226,6 +286,10 @@ func TestHypercatUnmarshalParse(t *testing.T) {
 		if cat.Description != testcase.expected.Description {
 			t.Errorf("Hypercat unmarshalling error, expected '%v', got '%v'", testcase.expected.Description, cat.Description)
 		}
+
+		if len(cat.Items) != len(testcase.expected.Items) {
+			t.Errorf("Hypercat unmarshalling error, expected '%v' items, got '%v'", len(testcase.expected.Items), len(cat.Items))
+		}
 	}
 
 	// test Parse helper
@@ -239,6 +303,10 @@ func TestHypercatUnmarshalParse(t *testing.T) {
 		if cat.Description != testcase.expected.Description {
 			t.Errorf("Hypercat unmarshalling error, expected '%v', got '%v'", testcase.expected.Description, cat.Description)
 		}
+
+		if len(cat.Items) != len(testcase.expected.Items) {
+			t.Errorf("Hypercat unmarshalling error, expected '%v' items, got '%v'", len(testcase.expected.Items), len(cat.Items))
+		}
 	}
 }
 
@@ -345,3 +413,29 @@ func TestVals(t *testing.T) {
 		t.Errorf("Item Vals error, expected '%v', got '%v'", expected, got)
 	}
 }
+
+func TestETag(t *testing.T) {
+	cat := NewHypercat("description")
+
+	etag1, err := cat.ETag()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(etag1, `"`) || !strings.HasSuffix(etag1, `"`) {
+		t.Errorf("ETag should be quoted, got '%v'", etag1)
+	}
+
+	etag2, _ := NewHypercat("description").ETag()
+
+	if etag1 != etag2 {
+		t.Errorf("ETag error, expected '%v', got '%v'", etag1, etag2)
+	}
+
+	cat.AddRel("relation", "value")
+	etag2, _ = cat.ETag()
+
+	if etag1 == etag2 {
+		t.Errorf("ETag should change when the catalogue changes")
+	}
+}
@@ -0,0 +1,39 @@
+package hypercat
+
+// Index is a secondary index over the items of a catalogue, kept up to date
+// incrementally as the catalogue is modified. Indexes are attached to a
+// catalogue with AddIndex, after which every mutation made through the
+// catalogue API is applied to them synchronously, before the corresponding
+// event is published to the catalogue's Feed.
+//
+// As with the Feed, changes made directly to the Items of a catalogue bypass
+// its indexes, which must then be brought up to date by calling Reset.
+type Index interface {
+	// Reset replaces the contents of the index with the given items.
+	Reset(items Items)
+
+	// Apply updates the index with the mutation described by the event.
+	Apply(ev Event)
+}
+
+// hrefRewriter is implemented by indexes keyed by the href of items, which are
+// given the new href of each item whose href is rewritten by Rebase or
+// ResolveHrefs, before being reset.
+type hrefRewriter interface {
+	rewriteHrefs(renamed map[string]string)
+}
+
+// AddIndex attaches an index to the catalogue, first resetting it to contain
+// the catalogue's current items.
+func (h *Hypercat) AddIndex(idx Index) {
+	idx.Reset(h.Items)
+	h.Indexes = append(h.Indexes, idx)
+}
+
+// resetIndexes resets every index attached to the catalogue to contain its
+// current items.
+func (h *Hypercat) resetIndexes() {
+	for _, idx := range h.Indexes {
+		idx.Reset(h.Items)
+	}
+}
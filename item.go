@@ -7,9 +7,11 @@ import (
 // Item is the representation of the Hypercat item object, which is the main
 // object stored within a catalogue instance.
 type Item struct {
-	Href        string   `json:"href"`
-	Metadata    Metadata `json:"item-metadata"`
-	Description string   `json:"-"` // Spec is unclear about whether there can be more than one description. We assume not.
+	Href         string                     `json:"href"`
+	Metadata     Metadata                   `json:"item-metadata"`
+	Description  string                     `json:"-"` // Spec is unclear about whether there can be more than one description. We assume one per language.
+	Descriptions map[string]string          `json:"-"` // Descriptions in languages other than DefaultLanguage, keyed by language tag.
+	Extra        map[string]json.RawMessage `json:"-"` // Unknown JSON fields, preserved when the item is marshalled.
 }
 
 // Items is a simple type alias for a slice of Item structs.
@@ -27,18 +29,48 @@ func NewItem(href, description string) *Item {
 // AddRel is a function for adding a Rel object to an item. This may result in
 // duplicated Rel keys as this is permitted by the Hypercat spec.
 func (item *Item) AddRel(rel, val string) {
-	item.Metadata = append(item.Metadata, Rel{Rel: rel, Val: val})
+	item.Metadata.Add(rel, val)
 }
 
-// ReplaceRel is a function that attempts to replace the value of a specific
-// Rel object if it is attached to this Item. If the Rel key isn't found this
-// will have no effect.
-func (item *Item) ReplaceRel(rel, val string) {
-	for i, relationship := range item.Metadata {
-		if relationship.Rel == rel {
-			item.Metadata[i] = Rel{Rel: rel, Val: val}
-		}
-	}
+// ReplaceRel is a function that attempts to replace the value of every Rel
+// object with the given key attached to this Item. Returns false, having no
+// effect, if the Rel key isn't found.
+func (item *Item) ReplaceRel(rel, val string) bool {
+	return item.Metadata.Replace(rel, val)
+}
+
+// SetRel sets the value of a Rel attached to this Item, replacing any existing
+// values or adding the Rel if it isn't found.
+func (item *Item) SetRel(rel, val string) {
+	item.Metadata.Set(rel, val)
+}
+
+// RemoveRel removes every Rel with the given key from this Item, returning the
+// number of Rels removed.
+func (item *Item) RemoveRel(rel string) int {
+	return item.Metadata.Remove(rel)
+}
+
+// RemoveRelVal removes every Rel with the given key and value from this Item,
+// returning the number of Rels removed.
+func (item *Item) RemoveRelVal(rel, val string) int {
+	return item.Metadata.RemoveVal(rel, val)
+}
+
+// HasRel reports whether a Rel with the given key is attached to this Item.
+func (item *Item) HasRel(rel string) bool {
+	return item.Metadata.Has(rel)
+}
+
+// clone returns a copy of the item which shares no metadata storage with the
+// original.
+func (item *Item) clone() *Item {
+	c := *item
+	c.Metadata = append(Metadata{}, item.Metadata...)
+	c.Descriptions = copyDescriptions(item.Descriptions)
+	c.Extra = copyExtra(item.Extra)
+
+	return &c
 }
 
 // IsCatalogue returns true if the Item is a Hypercat catalogue, false
@@ -56,42 +88,43 @@ func (item *Item) IsCatalogue() bool {
 // MarshalJSON returns the JSON encoding of an Item. This function is the the
 // required function for structs that implement the Marshaler interface.
 func (item *Item) MarshalJSON() ([]byte, error) {
-	metadata := item.Metadata
+	return marshalObject([]jsonField{
+		{key: "href", value: item.Href},
+		{key: "item-metadata", value: item.allMetadata()},
+	}, item.Extra, false)
+}
 
-	if item.Description != "" {
-		metadata = append(metadata, Rel{Rel: DescriptionRel, Val: item.Description})
-	}
+// allMetadata returns a copy of the item's metadata including the Rels stored
+// in dedicated fields, as they appear when serialized.
+func (item *Item) allMetadata() Metadata {
+	metadata := append(Metadata{}, item.Metadata...)
 
-	return json.Marshal(struct {
-		Href     string    `json:"href"`
-		Metadata *Metadata `json:"item-metadata"`
-	}{
-		Href:     item.Href,
-		Metadata: &metadata,
-	})
+	metadata = append(metadata, descriptionMetadata(item.Description, item.Descriptions)...)
+
+	return metadata
 }
 
 // UnmarshalJSON is the required function for structs that implement the
 // Unmarshaler interface.
 func (item *Item) UnmarshalJSON(b []byte) error {
-	type tempItem struct {
-		Href     string   `json:"href"`
-		Metadata Metadata `json:"item-metadata"`
-	}
+	var href string
+	var metadata Metadata
 
-	t := tempItem{}
-
-	err := json.Unmarshal(b, &t)
+	extra, err := unmarshalObject(b, map[string]interface{}{
+		"href":          &href,
+		"item-metadata": &metadata,
+	})
 
 	if err != nil {
 		return err
 	}
 
-	item.Href = t.Href
+	item.Href = href
+	item.Extra = extra
 
-	for _, rel := range t.Metadata {
-		if rel.Rel == DescriptionRel {
-			item.Description = rel.Val
+	for _, rel := range metadata {
+		if lang, ok := descriptionLang(rel.Rel); ok {
+			storeDescription(&item.Description, &item.Descriptions, lang, rel.Val)
 		} else {
 			item.Metadata = append(item.Metadata, rel)
 		}
@@ -101,7 +134,7 @@ func (item *Item) UnmarshalJSON(b []byte) error {
 		return ErrMissingHref
 	}
 
-	if item.Description == "" {
+	if item.Description == "" && len(item.Descriptions) == 0 {
 		return ErrMissingDescriptionRel
 	}
 
@@ -110,24 +143,10 @@ func (item *Item) UnmarshalJSON(b []byte) error {
 
 // Rels returns a slice containing all the Rel values of this item.
 func (item *Item) Rels() []string {
-	rels := make([]string, len(item.Metadata))
-
-	for i, rel := range item.Metadata {
-		rels[i] = rel.Rel
-	}
-
-	return rels
+	return item.Metadata.Rels()
 }
 
 // Vals returns a slice of all values that match the given rel value.
 func (item *Item) Vals(key string) []string {
-	vals := []string{}
-
-	for _, rel := range item.Metadata {
-		if rel.Rel == key {
-			vals = append(vals, rel.Val)
-		}
-	}
-
-	return vals
+	return item.Metadata.Vals(key)
 }
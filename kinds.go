@@ -0,0 +1,176 @@
+package hypercat
+
+import (
+	"math"
+	"mime"
+	"net/url"
+	"strconv"
+)
+
+// ItemKind identifies a common kind of resource described by catalogue items,
+// along with the rels that consumers rely on items of that kind to carry.
+type ItemKind int
+
+const (
+	// SensorStream is the kind of items describing a stream of sensor data,
+	// which must have an isContentType rel giving the media type of the data.
+	SensorStream ItemKind = iota
+
+	// SubCatalogue is the kind of items linking to another Hypercat catalogue,
+	// which must have HypercatMediaType as their isContentType.
+	SubCatalogue
+
+	// HomepageLink is the kind of items whose resource has a human readable
+	// homepage, which must be an absolute http or https URL.
+	HomepageLink
+
+	// GeolocatedDevice is the kind of items describing a device at a fixed
+	// location, which must have a valid WGS84 latitude and longitude.
+	GeolocatedDevice
+)
+
+// String returns the name of the item kind.
+func (k ItemKind) String() string {
+	switch k {
+	case SensorStream:
+		return "sensor stream"
+	case SubCatalogue:
+		return "sub-catalogue"
+	case HomepageLink:
+		return "homepage link"
+	case GeolocatedDevice:
+		return "geolocated device"
+	default:
+		return "ItemKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Validate checks that the item is a well-formed item of this kind, returning
+// ValidationErrors describing every problem found, or nil if there are none.
+// Every item must have an href and a description, and satisfy the rel
+// definitions of the DefaultRegistry.
+func (k ItemKind) Validate(item *Item) error {
+	errs := ValidationErrors{}
+
+	fail := func(err error) {
+		errs = append(errs, &ValidationError{Href: item.Href, Err: err})
+	}
+
+	if item.Href == "" {
+		fail(ErrMissingHref)
+	} else if _, err := url.Parse(item.Href); err != nil {
+		fail(err)
+	}
+
+	if err := DefaultRegistry.ValidateItem(item); err != nil {
+		errs = append(errs, err.(ValidationErrors)...)
+	}
+
+	switch k {
+	case SensorStream:
+		val, err := item.Metadata.value(ContentTypeRel)
+		if err != nil {
+			fail(err)
+		} else if _, _, err := mime.ParseMediaType(val); err != nil || val == HypercatMediaType {
+			fail(&RelError{Rel: ContentTypeRel, Val: val, Err: ErrValueNotAllowed})
+		}
+	case SubCatalogue:
+		if !item.IsCatalogue() {
+			fail(&RelError{Rel: ContentTypeRel, Val: HypercatMediaType, Err: ErrRelNotFound})
+		}
+	case HomepageLink:
+		val, err := item.Metadata.value(HomepageRel)
+		if err != nil {
+			fail(err)
+		} else if u, err := url.Parse(val); err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			fail(&RelError{Rel: HomepageRel, Val: val, Err: ErrValueNotAllowed})
+		}
+	case GeolocatedDevice:
+		checkCoordinate(item, LatitudeRel, 90, fail)
+		checkCoordinate(item, LongitudeRel, 180, fail)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// checkCoordinate reports a failure if the item has no value for the given
+// coordinate rel, or if its value is not a number within [-limit, limit].
+func checkCoordinate(item *Item, rel string, limit float64, fail func(err error)) {
+	val, err := item.Metadata.value(rel)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err == nil && !validCoordinate(f, limit) {
+		fail(&RelError{Rel: rel, Val: val, Err: ErrValueNotAllowed})
+	}
+}
+
+// validCoordinate reports whether f is a finite number within [-limit, limit].
+// NaN and infinite values are rejected explicitly, as they are accepted by
+// ParseFloat.
+func validCoordinate(f, limit float64) bool {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return false
+	}
+
+	return f >= -limit && f <= limit
+}
+
+// NewSensorStream is a constructor function that creates and returns an Item
+// describing a stream of sensor data with the given media type. Returns
+// ValidationErrors if the item isn't a well-formed SensorStream.
+func NewSensorStream(href, description, contentType string) (*Item, error) {
+	item := NewItem(href, description)
+	item.AddRel(ContentTypeRel, contentType)
+
+	return validKind(SensorStream, item)
+}
+
+// NewSubCatalogue is a constructor function that creates and returns an Item
+// linking to another Hypercat catalogue, for which IsCatalogue returns true.
+// Returns ValidationErrors if the item isn't a well-formed SubCatalogue.
+func NewSubCatalogue(href, description string) (*Item, error) {
+	item := NewItem(href, description)
+	item.AddRel(ContentTypeRel, HypercatMediaType)
+
+	return validKind(SubCatalogue, item)
+}
+
+// NewHomepageLink is a constructor function that creates and returns an Item
+// whose resource has the given homepage. Returns ValidationErrors if the item
+// isn't a well-formed HomepageLink.
+func NewHomepageLink(href, description, homepage string) (*Item, error) {
+	item := NewItem(href, description)
+	item.AddRel(HomepageRel, homepage)
+
+	return validKind(HomepageLink, item)
+}
+
+// NewGeolocatedDevice is a constructor function that creates and returns an
+// Item describing a device at the given WGS84 latitude and longitude. Returns
+// ValidationErrors if the item isn't a well-formed GeolocatedDevice.
+func NewGeolocatedDevice(href, description string, lat, lng float64) (*Item, error) {
+	item := NewItem(href, description)
+	item.SetFloat(LatitudeRel, lat)
+	item.SetFloat(LongitudeRel, lng)
+
+	return validKind(GeolocatedDevice, item)
+}
+
+// validKind returns the item if it is a well-formed item of the given kind, or
+// the errors describing why it isn't.
+func validKind(k ItemKind, item *Item) (*Item, error) {
+	err := k.Validate(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,111 @@
+package hypercat
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestItemKindConstructors(t *testing.T) {
+	stream, err := NewSensorStream("http://example.com/streams/1", "Temperature", "application/json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if val, _ := stream.Metadata.First(ContentTypeRel); val != "application/json" {
+		t.Errorf("Sensor stream content type error, expected '%v', got '%v'", "application/json", val)
+	}
+
+	sub, err := NewSubCatalogue("/parks", "Parks")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !sub.IsCatalogue() {
+		t.Errorf("Sub-catalogue should be a catalogue")
+	}
+
+	link, err := NewHomepageLink("/parks/1", "Hyde Park", "https://example.com/hyde-park")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if u, _ := link.URL(HomepageRel); u.String() != "https://example.com/hyde-park" {
+		t.Errorf("Homepage link error, got '%v'", u)
+	}
+
+	device, err := NewGeolocatedDevice("/devices/1", "Weather station", 51.5, -0.125)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if lat, _ := device.Float(LatitudeRel); lat != 51.5 {
+		t.Errorf("Geolocated device latitude error, expected '%v', got '%v'", 51.5, lat)
+	}
+}
+
+func TestItemKindConstructorErrors(t *testing.T) {
+	var testcases = []struct {
+		build    func() (*Item, error)
+		expected []error
+	}{
+		{func() (*Item, error) { return NewSensorStream("/s", "Stream", "") }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewSensorStream("/s", "Stream", HypercatMediaType) }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewSensorStream("", "", "text/csv") }, []error{ErrMissingHref, ErrTooFewValues}},
+		{func() (*Item, error) { return NewSubCatalogue("%zz", "Parks") }, []error{}},
+		{func() (*Item, error) { return NewHomepageLink("/h", "Home", "/relative") }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewHomepageLink("/h", "Home", "ftp://example.com") }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewGeolocatedDevice("/d", "Device", 91, 0) }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewGeolocatedDevice("/d", "Device", 0, -180.5) }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewGeolocatedDevice("/d", "Device", math.NaN(), 0) }, []error{ErrValueNotAllowed}},
+		{func() (*Item, error) { return NewGeolocatedDevice("/d", "Device", 0, math.Inf(-1)) }, []error{ErrValueNotAllowed}},
+	}
+
+	for i, testcase := range testcases {
+		item, err := testcase.build()
+
+		if item != nil || err == nil {
+			t.Errorf("Constructor %v should have failed, got '%v'", i, item)
+			continue
+		}
+
+		if _, ok := err.(ValidationErrors); !ok {
+			t.Errorf("Constructor %v error, expected ValidationErrors, got '%v'", i, err)
+		}
+
+		for _, target := range testcase.expected {
+			found := false
+
+			for _, e := range err.(ValidationErrors) {
+				found = found || errors.Is(e, target)
+			}
+
+			if !found {
+				t.Errorf("Constructor %v error, expected '%v' within '%v'", i, target, err)
+			}
+		}
+	}
+}
+
+func TestItemKindValidate(t *testing.T) {
+	item := NewItem("/foo", "Foo")
+
+	for _, kind := range []ItemKind{SensorStream, SubCatalogue, HomepageLink, GeolocatedDevice} {
+		err := kind.Validate(item)
+
+		errs, ok := err.(ValidationErrors)
+		if !ok || !errors.Is(errs[0], ErrRelNotFound) {
+			t.Errorf("%v validation error, expected '%v', got '%v'", kind, ErrRelNotFound, err)
+		}
+	}
+
+	item.AddRel(ContentTypeRel, HypercatMediaType)
+
+	if err := SubCatalogue.Validate(item); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if ItemKind(7).String() != "ItemKind(7)" {
+		t.Errorf("ItemKind string error, got '%v'", ItemKind(7))
+	}
+}
@@ -0,0 +1,73 @@
+package hypercat
+
+import (
+	"time"
+)
+
+// LastUpdated returns the time the item was last modified, as recorded by its
+// LastUpdatedRel. Returns a RelError wrapping ErrRelNotFound if the item
+// carries no timestamp.
+func (item *Item) LastUpdated() (time.Time, error) {
+	return item.Time(LastUpdatedRel)
+}
+
+// SetLastUpdated records the given time as the value of the item's
+// LastUpdatedRel, replacing any existing timestamp.
+func (item *Item) SetLastUpdated(t time.Time) {
+	item.SetTime(LastUpdatedRel, t)
+}
+
+// LastUpdated returns the time the catalogue was last modified, as recorded by
+// its LastUpdatedRel. Returns a RelError wrapping ErrRelNotFound if the
+// catalogue carries no timestamp.
+func (h *Hypercat) LastUpdated() (time.Time, error) {
+	return h.Time(LastUpdatedRel)
+}
+
+// SetLastUpdated records the given time as the value of the catalogue's
+// LastUpdatedRel, replacing any existing timestamp.
+func (h *Hypercat) SetLastUpdated(t time.Time) {
+	h.Metadata.Set(LastUpdatedRel, formatTime(t))
+}
+
+// ModifiedSince returns the items within the catalogue whose LastUpdatedRel is
+// later than the given time. Items without a valid timestamp are skipped.
+func (h *Hypercat) ModifiedSince(t time.Time) Items {
+	items := Items{}
+
+	for _, item := range h.Items {
+		updated, err := item.LastUpdated()
+		if err == nil && updated.After(t) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// touch records the current time from the catalogue's Clock against the given
+// item, if not nil, and the catalogue itself. It has no effect if the
+// catalogue has no Clock. Items carrying a SignatureRel are left untouched, as
+// changing their LastUpdatedRel would invalidate their signature.
+func (h *Hypercat) touch(item *Item) {
+	if h.Clock == nil {
+		return
+	}
+
+	now := h.Clock()
+
+	if item != nil && !item.HasRel(SignatureRel) {
+		item.SetLastUpdated(now)
+	}
+
+	h.SetLastUpdated(now)
+}
+
+// touchRel records the current time from the catalogue's Clock after the given
+// catalogue rel is modified, unless the rel is LastUpdatedRel itself, so that
+// timestamps set explicitly aren't overwritten.
+func (h *Hypercat) touchRel(rel string) {
+	if rel != LastUpdatedRel {
+		h.touch(nil)
+	}
+}
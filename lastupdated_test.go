@@ -0,0 +1,142 @@
+package hypercat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testClock returns a clock function that starts at the given time and
+// advances by a minute on every call.
+func testClock(start time.Time) func() time.Time {
+	now := start.Add(-time.Minute)
+
+	return func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+}
+
+func TestItemLastUpdated(t *testing.T) {
+	item := NewItem("/foo", "description")
+
+	_, err := item.LastUpdated()
+	if !errors.Is(err, ErrRelNotFound) {
+		t.Errorf("Item LastUpdated error, expected '%v', got '%v'", ErrRelNotFound, err)
+	}
+
+	now := time.Date(2016, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	item.SetLastUpdated(now)
+	item.SetLastUpdated(now.Add(time.Hour))
+
+	expected := Metadata{Rel{Rel: LastUpdatedRel, Val: "2016-03-01T13:30:00Z"}}
+
+	if !reflect.DeepEqual(item.Metadata, expected) {
+		t.Errorf("Item SetLastUpdated error, expected '%v', got '%v'", expected, item.Metadata)
+	}
+
+	got, err := item.LastUpdated()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !got.Equal(now.Add(time.Hour)) {
+		t.Errorf("Item LastUpdated error, expected '%v', got '%v'", now.Add(time.Hour), got)
+	}
+}
+
+func TestInvalidLastUpdated(t *testing.T) {
+	item := NewItem("/foo", "description")
+	item.AddRel(LastUpdatedRel, "yesterday")
+
+	_, err := item.LastUpdated()
+	if err == nil {
+		t.Errorf("Item LastUpdated should have returned an error")
+	}
+}
+
+func TestCatalogueTimestampsDisabled(t *testing.T) {
+	cat := NewHypercat("description")
+
+	cat.AddRel("relation", "value")
+
+	err := cat.AddItem(NewItem("/foo", "description"))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(cat.Vals(LastUpdatedRel)) != 0 || len(cat.Items[0].Vals(LastUpdatedRel)) != 0 {
+		t.Errorf("Timestamps should not be maintained without a clock")
+	}
+}
+
+func TestCatalogueTimestamps(t *testing.T) {
+	start := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cat := NewHypercat("description")
+	cat.Clock = testClock(start)
+
+	cat.AddRel("relation", "value")
+
+	updated, err := cat.LastUpdated()
+	if err != nil || !updated.Equal(start) {
+		t.Errorf("Catalogue LastUpdated error, expected '%v', got '%v' (%v)", start, updated, err)
+	}
+
+	item := NewItem("/foo", "description")
+
+	err = cat.AddItem(item)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(item.Metadata) != 0 {
+		t.Errorf("AddItem should not modify the supplied item")
+	}
+
+	err = cat.AddItem(NewItem("/bar", "description"))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = cat.ReplaceItem(NewItem("/foo", "new description"))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]time.Time{
+		"/bar": start.Add(2 * time.Minute),
+		"/foo": start.Add(3 * time.Minute),
+	}
+
+	for _, item := range cat.Items {
+		got, err := item.LastUpdated()
+		if err != nil || !got.Equal(expected[item.Href]) {
+			t.Errorf("Item LastUpdated error, expected '%v', got '%v' (%v)", expected[item.Href], got, err)
+		}
+	}
+
+	updated, err = cat.LastUpdated()
+	if err != nil || !updated.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("Catalogue LastUpdated error, expected '%v', got '%v' (%v)", start.Add(3*time.Minute), updated, err)
+	}
+
+	if len(cat.Vals(LastUpdatedRel)) != 1 {
+		t.Errorf("Catalogue should have a single timestamp, got '%v'", cat.Vals(LastUpdatedRel))
+	}
+
+	modified := cat.ModifiedSince(start.Add(2 * time.Minute))
+
+	if len(modified) != 1 || modified[0].Href != "/foo" {
+		t.Errorf("ModifiedSince error, expected '/foo', got '%v'", modified)
+	}
+
+	cat.SetTime(LastUpdatedRel, start)
+
+	updated, err = cat.LastUpdated()
+	if err != nil || !updated.Equal(start) {
+		t.Errorf("SetTime should not be overwritten by the Clock, expected '%v', got '%v' (%v)", start, updated, err)
+	}
+}
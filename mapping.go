@@ -0,0 +1,376 @@
+package hypercat
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	urlType             = reflect.TypeOf(&url.URL{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// fieldMapping describes how a struct field maps onto an Item.
+type fieldMapping struct {
+	index       []int
+	name        string
+	rel         string
+	href        bool
+	description bool
+	omitEmpty   bool
+}
+
+// MarshalItem returns an Item built from the fields of v, which must be a
+// struct or a pointer to a struct, according to their "hypercat" struct tags.
+//
+// The tag value is the rel a field maps to, so a field tagged
+// `hypercat:"urn:X-hypercat:rels:hasHomepage"` is stored as a Rel with that key.
+// The special tags `hypercat:",href"` and `hypercat:",description"` map a
+// string field to the Href and Description of the item. Fields without a tag,
+// or tagged "-", are ignored, and the fields of untagged embedded structs are
+// mapped as if they belonged to the outer struct.
+//
+// Fields may be strings, booleans, integers, floats, time.Time (stored as an
+// ISO 8601 timestamp), *url.URL, or any type implementing
+// encoding.TextMarshaler. Slices of these types map to repeated Rels, and
+// pointers to them are omitted when nil, including nil elements of slices.
+// Adding ",omitempty" to a tag omits the Rel when the field has its zero
+// value.
+//
+// Returns an error wrapping ErrUnsupportedType if a tag has no rel and is not
+// one of the special tags, or has an unknown option. Returns ErrMissingHref or
+// ErrMissingDescriptionRel if the resulting item has no href or description.
+func MarshalItem(v interface{}) (*Item, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a struct", ErrUnsupportedType, v)
+	}
+
+	mappings, err := mappingsFor(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	item := NewItem("", "")
+
+	for _, m := range mappings {
+		field := rv.FieldByIndex(m.index)
+
+		if m.href {
+			item.Href = field.String()
+			continue
+		}
+
+		if m.description {
+			item.Description = field.String()
+			continue
+		}
+
+		if m.omitEmpty && field.IsZero() {
+			continue
+		}
+
+		vals, err := formatField(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, m.name)
+		}
+
+		for _, val := range vals {
+			item.AddRel(m.rel, val)
+		}
+	}
+
+	if item.Href == "" {
+		return nil, ErrMissingHref
+	}
+
+	if item.Description == "" {
+		return nil, ErrMissingDescriptionRel
+	}
+
+	return item, nil
+}
+
+// UnmarshalItem stores the href, description and metadata of the item in the
+// fields of v, which must be a pointer to a struct, according to their
+// "hypercat" struct tags. See MarshalItem for details of the mapping.
+//
+// Scalar fields are set from the first Rel with a matching key, and slice
+// fields from all of them. Fields whose Rel isn't present are left unchanged.
+// Returns a RelError if a value can't be converted to the type of its field.
+func UnmarshalItem(item *Item, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a pointer to a struct", ErrUnsupportedType, v)
+	}
+
+	rv = rv.Elem()
+
+	mappings, err := mappingsFor(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mappings {
+		field := rv.FieldByIndex(m.index)
+
+		if m.href {
+			field.SetString(item.Href)
+			continue
+		}
+
+		if m.description {
+			field.SetString(item.Description)
+			continue
+		}
+
+		vals := item.Vals(m.rel)
+		if len(vals) == 0 {
+			continue
+		}
+
+		err := parseField(m.rel, vals, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mappingsFor returns the field mappings of the given struct type.
+func mappingsFor(t reflect.Type) ([]fieldMapping, error) {
+	mappings := []fieldMapping{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("hypercat")
+
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			embedded, err := mappingsFor(f.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range embedded {
+				m.index = append([]int{i}, m.index...)
+				mappings = append(mappings, m)
+			}
+
+			continue
+		}
+
+		if !tagged || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		m := fieldMapping{index: []int{i}, name: f.Name, rel: parts[0]}
+
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "href":
+				m.href = true
+			case "description":
+				m.description = true
+			case "omitempty":
+				m.omitEmpty = true
+			default:
+				return nil, fmt.Errorf("%w: field %s has unknown option %q", ErrUnsupportedType, f.Name, opt)
+			}
+		}
+
+		if m.rel == "" && !m.href && !m.description {
+			return nil, fmt.Errorf("%w: field %s has no rel", ErrUnsupportedType, f.Name)
+		}
+
+		if (m.href || m.description) && f.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: field %s must be a string", ErrUnsupportedType, f.Name)
+		}
+
+		mappings = append(mappings, m)
+	}
+
+	return mappings, nil
+}
+
+// formatField returns the rel values representing the given field.
+func formatField(field reflect.Value) ([]string, error) {
+	switch {
+	case field.Type() == urlType:
+		if field.IsNil() {
+			return nil, nil
+		}
+	case field.Kind() == reflect.Ptr:
+		if field.IsNil() {
+			return nil, nil
+		}
+
+		return formatField(field.Elem())
+	case field.Kind() == reflect.Slice:
+		vals := make([]string, 0, field.Len())
+
+		for i := 0; i < field.Len(); i++ {
+			elem := field.Index(i)
+			if elem.Kind() == reflect.Ptr && elem.IsNil() {
+				continue
+			}
+
+			val, err := formatValue(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			vals = append(vals, val)
+		}
+
+		return vals, nil
+	}
+
+	val, err := formatValue(field)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{val}, nil
+}
+
+// formatValue returns the rel value representing a single value.
+func formatValue(v reflect.Value) (string, error) {
+	switch {
+	case v.Type() == timeType:
+		return formatTime(v.Interface().(time.Time)), nil
+	case v.Type() == urlType:
+		return v.Interface().(*url.URL).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return formatInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+}
+
+// parseField stores the given rel values in a field.
+func parseField(rel string, vals []string, field reflect.Value) error {
+	switch {
+	case field.Type() == urlType:
+	case field.Kind() == reflect.Ptr:
+		v := reflect.New(field.Type().Elem())
+
+		err := parseField(rel, vals, v.Elem())
+		if err != nil {
+			return err
+		}
+
+		field.Set(v)
+
+		return nil
+	case field.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+
+		for i, val := range vals {
+			err := parseValue(rel, val, slice.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+
+		return nil
+	}
+
+	return parseValue(rel, vals[0], field)
+}
+
+// parseValue converts a rel value to the type of v and stores it.
+func parseValue(rel, val string, v reflect.Value) error {
+	switch {
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.Set(reflect.ValueOf(t))
+
+		return nil
+	case v.Type() == urlType:
+		u, err := url.Parse(val)
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.Set(reflect.ValueOf(u))
+
+		return nil
+	case v.Addr().Type().Implements(textUnmarshalerType):
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, v.Type().Bits())
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 10, v.Type().Bits())
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, v.Type().Bits())
+		if err != nil {
+			return relError(rel, val, err)
+		}
+
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+	}
+
+	return nil
+}
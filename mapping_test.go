@@ -0,0 +1,209 @@
+package hypercat
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testLocation struct {
+	Latitude  float64 `hypercat:"http://www.w3.org/2003/01/geo/wgs84_pos#lat"`
+	Longitude float64 `hypercat:"http://www.w3.org/2003/01/geo/wgs84_pos#long"`
+}
+
+type testLevel string
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(l))), nil
+}
+
+func (l *testLevel) UnmarshalText(b []byte) error {
+	*l = testLevel(strings.ToLower(string(b)))
+	return nil
+}
+
+type testSensor struct {
+	testLocation
+	URL         string     `hypercat:",href"`
+	Name        string     `hypercat:",description"`
+	ContentType string     `hypercat:"urn:X-hypercat:rels:isContentType"`
+	Homepage    *url.URL   `hypercat:"urn:X-hypercat:rels:hasHomepage"`
+	Tags        []string   `hypercat:"tag"`
+	Readings    []int      `hypercat:"reading"`
+	Active      bool       `hypercat:"active"`
+	Count       uint8      `hypercat:"count,omitempty"`
+	Installed   time.Time  `hypercat:"installed"`
+	Removed     *time.Time `hypercat:"removed"`
+	Level       testLevel  `hypercat:"level"`
+	Ignored     string     `hypercat:"-"`
+	Untagged    string
+}
+
+func TestMarshalItem(t *testing.T) {
+	homepage, _ := url.Parse("http://example.com/")
+
+	sensor := testSensor{
+		testLocation: testLocation{Latitude: 51.5, Longitude: -0.125},
+		URL:          "/sensor1",
+		Name:         "Sensor 1",
+		ContentType:  "application/json",
+		Homepage:     homepage,
+		Tags:         []string{"air", "quality"},
+		Active:       true,
+		Installed:    time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC),
+		Level:        "high",
+		Ignored:      "ignored",
+		Untagged:     "untagged",
+	}
+
+	item, err := MarshalItem(&sensor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if item.Href != "/sensor1" || item.Description != "Sensor 1" {
+		t.Errorf("MarshalItem error, unexpected href '%v' or description '%v'", item.Href, item.Description)
+	}
+
+	expected := Metadata{
+		Rel{Rel: LatitudeRel, Val: "51.5"},
+		Rel{Rel: LongitudeRel, Val: "-0.125"},
+		Rel{Rel: ContentTypeRel, Val: "application/json"},
+		Rel{Rel: HomepageRel, Val: "http://example.com/"},
+		Rel{Rel: "tag", Val: "air"},
+		Rel{Rel: "tag", Val: "quality"},
+		Rel{Rel: "active", Val: "true"},
+		Rel{Rel: "installed", Val: "2016-03-01T12:00:00Z"},
+		Rel{Rel: "level", Val: "HIGH"},
+	}
+
+	if !reflect.DeepEqual(item.Metadata, expected) {
+		t.Errorf("MarshalItem error, expected '%v', got '%v'", expected, item.Metadata)
+	}
+
+	decoded := testSensor{Ignored: "unchanged"}
+
+	err = UnmarshalItem(item, &decoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sensor.Ignored = "unchanged"
+	sensor.Untagged = ""
+
+	if !reflect.DeepEqual(decoded, sensor) {
+		t.Errorf("UnmarshalItem error, expected '%+v', got '%+v'", sensor, decoded)
+	}
+}
+
+func TestUnmarshalItemPointersAndSlices(t *testing.T) {
+	item := NewItem("/sensor1", "Sensor 1")
+	item.AddRel("reading", "1")
+	item.AddRel("reading", "2")
+	item.AddRel("removed", "2016-03-02T00:00:00Z")
+	item.AddRel("count", "7")
+
+	sensor := testSensor{}
+
+	err := UnmarshalItem(item, &sensor)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(sensor.Readings, []int{1, 2}) {
+		t.Errorf("UnmarshalItem error, expected '%v', got '%v'", []int{1, 2}, sensor.Readings)
+	}
+
+	if sensor.Removed == nil || !sensor.Removed.Equal(time.Date(2016, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("UnmarshalItem error, unexpected time '%v'", sensor.Removed)
+	}
+
+	if sensor.Count != 7 {
+		t.Errorf("UnmarshalItem error, expected '%v', got '%v'", 7, sensor.Count)
+	}
+}
+
+func TestMarshalItemNilSliceElements(t *testing.T) {
+	homepage, _ := url.Parse("http://example.com")
+
+	item, err := MarshalItem(struct {
+		Href      string     `hypercat:",href"`
+		Name      string     `hypercat:",description"`
+		Homepages []*url.URL `hypercat:"urn:X-hypercat:rels:hasHomepage"`
+	}{"/foo", "Foo", []*url.URL{nil, homepage, nil}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := item.Vals(HomepageRel); !reflect.DeepEqual(got, []string{"http://example.com"}) {
+		t.Errorf("MarshalItem error, expected '%v', got '%v'", []string{"http://example.com"}, got)
+	}
+}
+
+func TestUnmarshalItemErrors(t *testing.T) {
+	item := NewItem("/sensor1", "Sensor 1")
+	item.AddRel("count", "300")
+
+	err := UnmarshalItem(item, &testSensor{})
+
+	relErr, ok := err.(*RelError)
+	if !ok || relErr.Rel != "count" || relErr.Val != "300" {
+		t.Errorf("UnmarshalItem error, expected RelError for count, got '%v'", err)
+	}
+
+	err = UnmarshalItem(item, testSensor{})
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("UnmarshalItem error, expected '%v', got '%v'", ErrUnsupportedType, err)
+	}
+
+	err = UnmarshalItem(item, &struct {
+		Values map[string]string `hypercat:"count"`
+	}{})
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("UnmarshalItem error, expected '%v', got '%v'", ErrUnsupportedType, err)
+	}
+}
+
+func TestMarshalItemErrors(t *testing.T) {
+	var testcases = []struct {
+		input    interface{}
+		expected error
+	}{
+		{"string", ErrUnsupportedType},
+		{struct {
+			Href int `hypercat:",href"`
+		}{}, ErrUnsupportedType},
+		{struct {
+			Href   string            `hypercat:",href"`
+			Name   string            `hypercat:",description"`
+			Values map[string]string `hypercat:"values"`
+		}{"/foo", "Foo", nil}, ErrUnsupportedType},
+		{struct {
+			Href  string `hypercat:",href"`
+			Name  string `hypercat:",description"`
+			Count int    `hypercat:",omitempty"`
+		}{"/foo", "Foo", 1}, ErrUnsupportedType},
+		{struct {
+			Href  string `hypercat:",href"`
+			Name  string `hypercat:",description"`
+			Count int    `hypercat:"count,omitzero"`
+		}{"/foo", "Foo", 1}, ErrUnsupportedType},
+		{struct {
+			Name string `hypercat:",description"`
+		}{"Foo"}, ErrMissingHref},
+		{struct {
+			Href string `hypercat:",href"`
+		}{"/foo"}, ErrMissingDescriptionRel},
+	}
+
+	for _, testcase := range testcases {
+		_, err := MarshalItem(testcase.input)
+
+		if !errors.Is(err, testcase.expected) {
+			t.Errorf("MarshalItem error, expected '%v', got '%v'", testcase.expected, err)
+		}
+	}
+}
@@ -18,3 +18,138 @@ func NewRel(rel, val string) *Rel {
 		Val: val,
 	}
 }
+
+// Add appends a Rel to the metadata. This may result in duplicated Rel keys as
+// this is permitted by the Hypercat spec.
+func (m *Metadata) Add(key, val string) {
+	*m = append(*m, Rel{Rel: key, Val: val})
+}
+
+// Has reports whether the metadata contains a Rel matching the given key.
+func (m Metadata) Has(key string) bool {
+	_, ok := m.First(key)
+	return ok
+}
+
+// HasVal reports whether the metadata contains a Rel matching the given key
+// and value.
+func (m Metadata) HasVal(key, val string) bool {
+	for _, rel := range m {
+		if rel.Rel == key && rel.Val == val {
+			return true
+		}
+	}
+
+	return false
+}
+
+// First returns the value of the first Rel matching the given key, and whether
+// any such Rel was found.
+func (m Metadata) First(key string) (string, bool) {
+	for _, rel := range m {
+		if rel.Rel == key {
+			return rel.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// Rels returns the keys of all Rels within the metadata, in order.
+func (m Metadata) Rels() []string {
+	rels := make([]string, len(m))
+
+	for i, rel := range m {
+		rels[i] = rel.Rel
+	}
+
+	return rels
+}
+
+// Vals returns the values of all Rels matching the given key.
+func (m Metadata) Vals(key string) []string {
+	vals := []string{}
+
+	for _, rel := range m {
+		if rel.Rel == key {
+			vals = append(vals, rel.Val)
+		}
+	}
+
+	return vals
+}
+
+// Set replaces the value of the first Rel matching the given key, removing any
+// duplicates, or appends a new Rel if the key isn't found.
+func (m *Metadata) Set(key, val string) {
+	found := false
+	metadata := (*m)[:0]
+
+	for _, rel := range *m {
+		if rel.Rel == key {
+			if found {
+				continue
+			}
+
+			rel.Val = val
+			found = true
+		}
+
+		metadata = append(metadata, rel)
+	}
+
+	if !found {
+		metadata = append(metadata, Rel{Rel: key, Val: val})
+	}
+
+	*m = metadata
+}
+
+// Replace replaces the value of every Rel matching the given key, returning
+// whether any such Rel was found. Unlike Set, Replace keeps duplicates and
+// never adds a Rel.
+func (m Metadata) Replace(key, val string) bool {
+	replaced := false
+
+	for i := range m {
+		if m[i].Rel == key {
+			m[i].Val = val
+			replaced = true
+		}
+	}
+
+	return replaced
+}
+
+// Remove deletes every Rel matching the given key, returning the number of
+// Rels removed.
+func (m *Metadata) Remove(key string) int {
+	return m.removeWhere(func(rel Rel) bool {
+		return rel.Rel == key
+	})
+}
+
+// RemoveVal deletes every Rel matching the given key and value, returning the
+// number of Rels removed.
+func (m *Metadata) RemoveVal(key, val string) int {
+	return m.removeWhere(func(rel Rel) bool {
+		return rel.Rel == key && rel.Val == val
+	})
+}
+
+// removeWhere deletes every Rel for which the given predicate returns true,
+// returning the number of Rels removed.
+func (m *Metadata) removeWhere(pred func(Rel) bool) int {
+	metadata := (*m)[:0]
+
+	for _, rel := range *m {
+		if !pred(rel) {
+			metadata = append(metadata, rel)
+		}
+	}
+
+	removed := len(*m) - len(metadata)
+	*m = metadata
+
+	return removed
+}
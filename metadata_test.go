@@ -2,6 +2,7 @@ package hypercat
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -89,3 +90,106 @@ func TestMetadataMarshalling(t *testing.T) {
 		t.Errorf("Metadata marshalling error, expected '%v', got '%v'", expected, string(bytes))
 	}
 }
+
+func TestMetadataSet(t *testing.T) {
+	metadata := Metadata{
+		*NewRel("relation1", "value1"),
+		*NewRel("relation2", "value2"),
+		*NewRel("relation1", "value3"),
+	}
+
+	metadata.Set("relation1", "newvalue")
+	metadata.Set("relation3", "value4")
+
+	expected := Metadata{
+		*NewRel("relation1", "newvalue"),
+		*NewRel("relation2", "value2"),
+		*NewRel("relation3", "value4"),
+	}
+
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("Metadata set error, expected '%v', got '%v'", expected, metadata)
+	}
+}
+
+func TestMetadataMembership(t *testing.T) {
+	metadata := Metadata{}
+	metadata.Add("relation1", "value1")
+	metadata.Add("relation1", "value2")
+
+	var testcases = []struct {
+		got      bool
+		expected bool
+	}{
+		{metadata.Has("relation1"), true},
+		{metadata.Has("relation2"), false},
+		{metadata.HasVal("relation1", "value2"), true},
+		{metadata.HasVal("relation1", "value3"), false},
+	}
+
+	for i, testcase := range testcases {
+		if testcase.got != testcase.expected {
+			t.Errorf("Metadata membership error in case %d, expected '%v', got '%v'", i, testcase.expected, testcase.got)
+		}
+	}
+
+	val, ok := metadata.First("relation1")
+	if !ok || val != "value1" {
+		t.Errorf("Metadata First error, expected 'value1', got '%v'", val)
+	}
+}
+
+func TestMetadataReplace(t *testing.T) {
+	metadata := Metadata{
+		*NewRel("relation1", "value1"),
+		*NewRel("relation2", "value2"),
+		*NewRel("relation1", "value3"),
+	}
+
+	if !metadata.Replace("relation1", "newvalue") {
+		t.Errorf("Metadata Replace should report that the rel was found")
+	}
+
+	if metadata.Replace("relation3", "value4") {
+		t.Errorf("Metadata Replace should report that the rel was not found")
+	}
+
+	expected := Metadata{
+		*NewRel("relation1", "newvalue"),
+		*NewRel("relation2", "value2"),
+		*NewRel("relation1", "newvalue"),
+	}
+
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("Metadata replace error, expected '%v', got '%v'", expected, metadata)
+	}
+}
+
+func TestMetadataRemove(t *testing.T) {
+	metadata := Metadata{
+		*NewRel("relation1", "value1"),
+		*NewRel("relation2", "value2"),
+		*NewRel("relation1", "value3"),
+		*NewRel("relation2", "value4"),
+	}
+
+	removed := metadata.RemoveVal("relation2", "value4")
+	if removed != 1 {
+		t.Errorf("Metadata RemoveVal error, expected '1', got '%v'", removed)
+	}
+
+	removed = metadata.Remove("relation1")
+	if removed != 2 {
+		t.Errorf("Metadata Remove error, expected '2', got '%v'", removed)
+	}
+
+	expected := Metadata{*NewRel("relation2", "value2")}
+
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("Metadata remove error, expected '%v', got '%v'", expected, metadata)
+	}
+
+	if metadata.Remove("relation1") != 0 {
+		t.Errorf("Removing a missing rel should remove nothing")
+	}
+}
@@ -0,0 +1,322 @@
+package hypercat
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMirrorInterval is the interval between synchronizations used by a
+// Mirror whose Interval is not positive.
+const DefaultMirrorInterval = time.Minute
+
+// Store is a catalogue whose modifications are coordinated by its owner, such
+// as a Handler serving it.
+type Store interface {
+	// Update calls fn with the catalogue, which fn may modify.
+	Update(fn func(cat *Hypercat) error) error
+}
+
+// Mirror keeps a local catalogue synchronized with a remote one. Each
+// synchronization fetches every page of the remote catalogue, compares its
+// items with those of the local catalogue by href, and applies only the
+// differences through the catalogue API, so that the local catalogue's Feed
+// and indexes see just the items that changed.
+//
+// Requests are made conditional on the ETag and Last-Modified headers of the
+// previous response, so that servers supporting them, such as Handler, can
+// respond with 304 Not Modified when nothing has changed.
+//
+// The local catalogue's metadata is left untouched. Items are copied exactly
+// as published, even when later pages are at a different location, so that
+// signed items still verify. Local and remote items are matched by their hrefs
+// resolved against the URL of the remote catalogue's first page. It is safe
+// for concurrent use.
+type Mirror struct {
+	Client   *Client
+	URL      string        // URL of the remote catalogue.
+	Store    Store         // Local catalogue receiving the changes.
+	Interval time.Duration // Interval between synchronizations made by Run.
+
+	// Clock is an optional source of the current time, used to record the
+	// time of each synchronization. Defaults to time.Now.
+	Clock func() time.Time
+
+	syncMu       sync.Mutex // Serializes synchronizations, guarding the fields below.
+	etag         string
+	lastModified string
+
+	mu     sync.Mutex // Guards status.
+	status MirrorStatus
+}
+
+// MirrorStatus describes the synchronizations performed by a Mirror.
+type MirrorStatus struct {
+	Syncs       int       // Number of successful synchronizations.
+	Failures    int       // Number of consecutive failed synchronizations.
+	LastAttempt time.Time // Time of the most recent synchronization attempt.
+	LastSuccess time.Time // Time of the most recent successful synchronization.
+	NotModified bool      // Whether the remote catalogue was unchanged at the last successful synchronization.
+	Added       int       // Number of items added by the last successful synchronization.
+	Replaced    int       // Number of items replaced by the last successful synchronization.
+	Removed     int       // Number of items removed by the last successful synchronization.
+	Err         error     // Error encountered by the most recent attempt, or nil if it succeeded.
+}
+
+// NewMirror is a constructor function that creates and returns a Mirror
+// instance copying the catalogue at the given URL into the given store.
+func NewMirror(client *Client, rawurl string, store Store) *Mirror {
+	return &Mirror{
+		Client:   client,
+		URL:      rawurl,
+		Store:    store,
+		Interval: DefaultMirrorInterval,
+	}
+}
+
+// Status returns the status of the mirror's synchronizations.
+func (m *Mirror) Status() MirrorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.status
+}
+
+// Sync performs a single synchronization, returning any error encountered.
+// The error is also recorded in the mirror's status.
+func (m *Mirror) Sync(ctx context.Context) error {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	attempt := m.now()
+	changes, err := m.sync(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status.LastAttempt = attempt
+	m.status.Err = err
+
+	if err != nil {
+		m.status.Failures++
+		return err
+	}
+
+	m.status.Syncs++
+	m.status.Failures = 0
+	m.status.LastSuccess = attempt
+	m.status.NotModified = changes == nil
+
+	if changes == nil {
+		changes = &mirrorChanges{}
+	}
+
+	m.status.Added, m.status.Replaced, m.status.Removed = changes.added, changes.replaced, changes.removed
+
+	return nil
+}
+
+// mirrorChanges counts the changes applied by a synchronization.
+type mirrorChanges struct {
+	added    int
+	replaced int
+	removed  int
+}
+
+// Run synchronizes the mirror immediately and then after every Interval,
+// until the context is cancelled, returning the context's error. Failed
+// synchronizations are recorded in the mirror's status and retried at the
+// next interval.
+func (m *Mirror) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultMirrorInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync fetches the remote catalogue and applies its changes to the store,
+// returning the changes applied or nil if the remote catalogue wasn't
+// modified. It must be called with the sync lock held.
+func (m *Mirror) sync(ctx context.Context) (*mirrorChanges, error) {
+	req, err := m.Client.newRequest(ctx, "GET", m.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.etag != "" {
+		req.Header.Set("If-None-Match", m.etag)
+	}
+
+	if m.lastModified != "" {
+		req.Header.Set("If-Modified-Since", m.lastModified)
+	}
+
+	resp, err := m.Client.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{URL: m.URL, StatusCode: resp.StatusCode}
+	}
+
+	base := req.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+
+	remote, err := Parse(resp.Body, WithBaseURL(base))
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := nextPage(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != "" {
+		err = m.Client.pages(ctx, req.URL, next, func(page *Hypercat) error {
+			remote.Items = append(remote.Items, page.Items...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var changes *mirrorChanges
+
+	err = m.Store.Update(func(cat *Hypercat) error {
+		changes, err = applyChanges(cat, remote)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.etag = resp.Header.Get("ETag")
+	m.lastModified = resp.Header.Get("Last-Modified")
+
+	return changes, nil
+}
+
+// applyChanges updates the local catalogue to contain the items of the remote
+// one, adding, replacing and removing only the items that differ. Items are
+// matched by their hrefs resolved against the remote catalogue's BaseURL, but
+// stored with their hrefs unchanged.
+func applyChanges(cat, remote *Hypercat) (*mirrorChanges, error) {
+	changes := &mirrorChanges{}
+	local := make(map[string]int, len(cat.Items))
+
+	for i := range cat.Items {
+		key, err := mirrorKey(remote, cat.Items[i].Href)
+		if err != nil {
+			return nil, err
+		}
+
+		local[key] = i
+	}
+
+	keys := make(map[string]bool, len(remote.Items))
+
+	for i := range remote.Items {
+		item := &remote.Items[i]
+
+		key, err := mirrorKey(remote, item.Href)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[key] = true
+
+		index, ok := local[key]
+		if !ok {
+			cat.storeItem(-1, item)
+			local[key] = len(cat.Items) - 1
+			changes.added++
+
+			continue
+		}
+
+		same, err := sameItem(&cat.Items[index], item, cat.Clock != nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if !same {
+			cat.storeItem(index, item)
+			changes.replaced++
+		}
+	}
+
+	changes.removed = cat.RemoveItemsWhere(func(item *Item) bool {
+		key, err := mirrorKey(remote, item.Href)
+		return err != nil || !keys[key]
+	})
+
+	return changes, nil
+}
+
+// mirrorKey returns the key matching local and remote items with the given
+// href, which is the href resolved against the remote catalogue's BaseURL.
+func mirrorKey(remote *Hypercat, href string) (string, error) {
+	u, err := remote.ResolveHref(href)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// sameItem reports whether two items have the same canonical encoding,
+// optionally ignoring their LastUpdatedRel, which is maintained by
+// catalogues with a Clock.
+func sameItem(a, b *Item, ignoreLastUpdated bool) (bool, error) {
+	if ignoreLastUpdated {
+		a, b = a.clone(), b.clone()
+		a.RemoveRel(LastUpdatedRel)
+		b.RemoveRel(LastUpdatedRel)
+	}
+
+	x, err := a.CanonicalJSON()
+	if err != nil {
+		return false, err
+	}
+
+	y, err := b.CanonicalJSON()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(x, y), nil
+}
+
+// now returns the current time according to the mirror's Clock.
+func (m *Mirror) now() time.Time {
+	if m.Clock == nil {
+		return time.Now()
+	}
+
+	return m.Clock()
+}
@@ -0,0 +1,186 @@
+package hypercat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMirrorSync(t *testing.T) {
+	remote := NewHandler(testCatalogue(5))
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		remote.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	local := NewHypercat("Local catalogue")
+	local.Feed = NewFeed(0)
+	local.AddItem(NewItem("/stale", "Stale item"))
+
+	mirror := NewMirror(NewClient(), server.URL+"/cat?limit=2", NewHandler(local))
+	ctx := context.Background()
+
+	err := mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := hrefs(local.Items); !reflect.DeepEqual(got, []string{"/0", "/1", "/2", "/3", "/4"}) {
+		t.Errorf("Mirror sync error, unexpected items '%v'", got)
+	}
+
+	status := mirror.Status()
+	if status.Syncs != 1 || status.Added != 5 || status.Removed != 1 || status.NotModified {
+		t.Errorf("Mirror status error, got '%+v'", status)
+	}
+
+	requests = 0
+	seq := local.Feed.Seq()
+
+	err = mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status := mirror.Status(); !status.NotModified || status.Syncs != 2 || requests != 1 || local.Feed.Seq() != seq {
+		t.Errorf("Mirror should make a single conditional request, got %v requests and status '%+v'", requests, status)
+	}
+
+	remote.Update(func(cat *Hypercat) error {
+		cat.ReplaceItem(NewItem("/1", "Changed item"))
+		cat.RemoveItem("/3")
+		return cat.AddItem(NewItem("/5", "New item"))
+	})
+
+	err = mirror.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	status = mirror.Status()
+	if status.Added != 1 || status.Replaced != 1 || status.Removed != 1 || status.NotModified {
+		t.Errorf("Mirror status error, got '%+v'", status)
+	}
+
+	events, _ := local.Feed.Since(seq)
+	types := []EventType{}
+
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+
+	if !reflect.DeepEqual(types, []EventType{ItemReplaced, ItemAdded, ItemRemoved}) {
+		t.Errorf("Mirror should only apply the changes, got events '%v'", types)
+	}
+
+	if local.Items[1].Description != "Changed item" {
+		t.Errorf("Mirror sync error, expected '%v', got '%v'", "Changed item", local.Items[1].Description)
+	}
+}
+
+func TestMirrorSignedItems(t *testing.T) {
+	key := testKeys(t)[0]
+
+	remote := NewHypercat("Remote catalogue")
+
+	for _, href := range []string{"sensors/1", "/sensors/2", "http://example.com/sensors/3"} {
+		item := NewItem(href, "Signed item")
+
+		err := item.Sign(key)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		remote.AddItem(item)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/catalogues/cat", NewHandler(remote))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	local := NewHypercat("Local catalogue")
+	local.AddItem(NewItem(server.URL+"/catalogues/sensors/1", "Mirrored item"))
+
+	mirror := NewMirror(NewClient(), server.URL+"/catalogues/cat?limit=1", NewHandler(local))
+
+	err := mirror.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := hrefs(local.Items); !reflect.DeepEqual(got, hrefs(remote.Items)) {
+		t.Errorf("Mirror hrefs error, expected '%v', got '%v'", hrefs(remote.Items), got)
+	}
+
+	if status := mirror.Status(); status.Added != 2 || status.Replaced != 1 || status.Removed != 0 {
+		t.Errorf("Mirror should match items by their resolved hrefs, got status '%+v'", status)
+	}
+
+	for i := range local.Items {
+		err := local.Items[i].Verify(key.Public())
+		if err != nil {
+			t.Errorf("Mirrored item '%v' verification error: %v", local.Items[i].Href, err)
+		}
+	}
+}
+
+func TestMirrorSyncError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	now := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mirror := NewMirror(NewClient(), server.URL, NewHandler(NewHypercat("Local catalogue")))
+	mirror.Clock = func() time.Time { return now }
+
+	for i := 1; i <= 2; i++ {
+		err := mirror.Sync(context.Background())
+
+		status := mirror.Status()
+		if status.Err != err || status.Failures != i || !status.LastAttempt.Equal(now) || !status.LastSuccess.IsZero() {
+			t.Errorf("Mirror status error, got '%+v'", status)
+		}
+
+		statusErr, ok := err.(*StatusError)
+		if !ok || statusErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Mirror sync error, expected status error, got '%v'", err)
+		}
+	}
+}
+
+func TestMirrorRun(t *testing.T) {
+	server := httptest.NewServer(NewHandler(testCatalogue(2)))
+	defer server.Close()
+
+	local := NewHypercat("Local catalogue")
+
+	mirror := NewMirror(NewClient(), server.URL, NewHandler(local))
+	mirror.Interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- mirror.Run(ctx) }()
+
+	for mirror.Status().Syncs < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Mirror run error, expected '%v', got '%v'", context.Canceled, err)
+	}
+
+	if len(local.Items) != 2 {
+		t.Errorf("Mirror run error, expected 2 items, got '%v'", len(local.Items))
+	}
+}
@@ -0,0 +1,126 @@
+package hypercat
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// withItems returns a copy of the catalogue's description, content type,
+// metadata and base URL containing the given items. The copy shares no metadata storage
+// with the original, and has no Feed or Clock attached.
+func (h *Hypercat) withItems(items Items) *Hypercat {
+	return &Hypercat{
+		Items:        items,
+		Metadata:     append(Metadata{}, h.Metadata...),
+		Description:  h.Description,
+		Descriptions: copyDescriptions(h.Descriptions),
+		ContentType:  h.ContentType,
+		Extra:        copyExtra(h.Extra),
+		BaseURL:      copyURL(h.BaseURL),
+	}
+}
+
+// Paginate returns a catalogue containing at most limit items of this
+// catalogue starting at offset, along with the offset of the following page,
+// or -1 if there are no more items. A non positive limit returns all remaining
+// items.
+func (h *Hypercat) Paginate(offset, limit int) (*Hypercat, int) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset > len(h.Items) {
+		offset = len(h.Items)
+	}
+
+	end := len(h.Items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	next := end
+	if next == len(h.Items) {
+		next = -1
+	}
+
+	return h.withItems(append(Items{}, h.Items[offset:end]...)), next
+}
+
+// PaginateCursor returns a catalogue containing at most limit items of this
+// catalogue following the position identified by cursor, along with an opaque
+// cursor for the following page, or "" if there are no more items. An empty
+// cursor starts from the first item.
+//
+// Cursors identify the last item returned rather than a numeric position, so
+// adding or removing earlier items between requests doesn't cause items to be
+// skipped or repeated, provided that the last item returned still exists. If
+// it has been removed, the following page starts where that item was, which
+// is exact only if no earlier items were added or removed as well; otherwise
+// items may be skipped or repeated. Returns ErrInvalidCursor if the cursor
+// can't be decoded.
+func (h *Hypercat) PaginateCursor(cursor string, limit int) (*Hypercat, string, error) {
+	offset := 0
+
+	if cursor != "" {
+		var err error
+
+		offset, err = h.cursorOffset(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	page, next := h.Paginate(offset, limit)
+	if next == -1 {
+		return page, "", nil
+	}
+
+	return page, encodeCursor(next, h.Items[next-1].Href), nil
+}
+
+// encodeCursor returns an opaque cursor recording the offset of the next page
+// and the href of the last item on the current page.
+func encodeCursor(offset int, href string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + href))
+}
+
+// cursorOffset returns the offset of the first item following the position
+// identified by the given cursor. The href recorded in the cursor is used to
+// locate the position if the item has moved. If the item no longer exists,
+// the position it was recorded at is returned, which is now occupied by the
+// item that followed it.
+func (h *Hypercat) cursorOffset(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 1 {
+		return 0, ErrInvalidCursor
+	}
+
+	href := parts[1]
+
+	if offset <= len(h.Items) && h.Items[offset-1].Href == href {
+		return offset, nil
+	}
+
+	for i, item := range h.Items {
+		if item.Href == href {
+			return i + 1, nil
+		}
+	}
+
+	if offset > len(h.Items) {
+		return len(h.Items), nil
+	}
+
+	return offset - 1, nil
+}
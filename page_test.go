@@ -0,0 +1,130 @@
+package hypercat
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// testCatalogue returns a catalogue containing n items with hrefs /0 to /n-1.
+func testCatalogue(n int) *Hypercat {
+	cat := NewHypercat("Catalogue description")
+
+	for i := 0; i < n; i++ {
+		cat.AddItem(NewItem(fmt.Sprintf("/%d", i), fmt.Sprintf("Item %d", i)))
+	}
+
+	return cat
+}
+
+func hrefs(items Items) []string {
+	result := []string{}
+
+	for _, item := range items {
+		result = append(result, item.Href)
+	}
+
+	return result
+}
+
+func TestPaginate(t *testing.T) {
+	cat := testCatalogue(5)
+	cat.AddRel("relation", "value")
+
+	var testcases = []struct {
+		offset   int
+		limit    int
+		expected []string
+		next     int
+	}{
+		{0, 2, []string{"/0", "/1"}, 2},
+		{2, 2, []string{"/2", "/3"}, 4},
+		{4, 2, []string{"/4"}, -1},
+		{3, 0, []string{"/3", "/4"}, -1},
+		{7, 2, []string{}, -1},
+	}
+
+	for _, testcase := range testcases {
+		page, next := cat.Paginate(testcase.offset, testcase.limit)
+
+		if !reflect.DeepEqual(hrefs(page.Items), testcase.expected) {
+			t.Errorf("Paginate error, expected '%v', got '%v'", testcase.expected, hrefs(page.Items))
+		}
+
+		if next != testcase.next {
+			t.Errorf("Paginate next error, expected '%v', got '%v'", testcase.next, next)
+		}
+
+		if page.Description != cat.Description || !reflect.DeepEqual(page.Metadata, cat.Metadata) {
+			t.Errorf("Paginate should preserve the catalogue metadata")
+		}
+	}
+}
+
+func TestPaginateCursor(t *testing.T) {
+	cat := testCatalogue(5)
+
+	page, cursor, err := cat.PaginateCursor("", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(hrefs(page.Items), []string{"/0", "/1"}) {
+		t.Errorf("PaginateCursor error, got '%v'", hrefs(page.Items))
+	}
+
+	// inserting an item before the cursor position must not repeat items
+	cat.Items = append(Items{*NewItem("/new", "New")}, cat.Items...)
+
+	page, cursor, err = cat.PaginateCursor(cursor, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(hrefs(page.Items), []string{"/2", "/3"}) {
+		t.Errorf("PaginateCursor error, got '%v'", hrefs(page.Items))
+	}
+
+	page, cursor, err = cat.PaginateCursor(cursor, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(hrefs(page.Items), []string{"/4"}) || cursor != "" {
+		t.Errorf("PaginateCursor error, got '%v' '%v'", hrefs(page.Items), cursor)
+	}
+
+	var testcases = []struct {
+		removed  []string
+		expected []string
+	}{
+		{[]string{"/1"}, []string{"/2", "/3"}},
+		// removing an earlier item as well skips the item that followed
+		{[]string{"/0", "/1"}, []string{"/3", "/4"}},
+	}
+
+	for _, testcase := range testcases {
+		cat := testCatalogue(5)
+		_, cursor, _ := cat.PaginateCursor("", 2)
+
+		for _, href := range testcase.removed {
+			cat.RemoveItem(href)
+		}
+
+		page, _, err := cat.PaginateCursor(cursor, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(hrefs(page.Items), testcase.expected) {
+			t.Errorf("PaginateCursor error after removing '%v', expected '%v', got '%v'", testcase.removed, testcase.expected, hrefs(page.Items))
+		}
+	}
+
+	for _, invalid := range []string{"!!", encodeCursor(0, "/0")[:2], "MA"} {
+		_, _, err = cat.PaginateCursor(invalid, 2)
+		if err != ErrInvalidCursor {
+			t.Errorf("PaginateCursor error for '%v', expected '%v', got '%v'", invalid, ErrInvalidCursor, err)
+		}
+	}
+}
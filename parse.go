@@ -0,0 +1,408 @@
+package hypercat
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ParseOption is a function that configures optional behaviour of Parse.
+type ParseOption func(*parseOptions)
+
+// parseOptions holds the configuration built from a list of ParseOptions.
+type parseOptions struct {
+	baseURL              *url.URL
+	catalogueKey         crypto.PublicKey
+	itemKey              crypto.PublicKey
+	disallowUnknownField bool
+	rejectDuplicateHref  bool
+	rejectDuplicateDescr bool
+}
+
+// DisallowUnknownFields returns a ParseOption that makes Parse reject
+// documents containing catalogue or item fields other than those defined by
+// the Hypercat specification, instead of preserving them in Extra.
+func DisallowUnknownFields() ParseOption {
+	return func(o *parseOptions) {
+		o.disallowUnknownField = true
+	}
+}
+
+// RejectDuplicateHrefs returns a ParseOption that makes Parse reject documents
+// containing more than one item with the same href, with an error wrapping
+// ErrDuplicateHref.
+func RejectDuplicateHrefs() ParseOption {
+	return func(o *parseOptions) {
+		o.rejectDuplicateHref = true
+	}
+}
+
+// RejectDuplicateDescriptions returns a ParseOption that makes Parse reject
+// documents in which the catalogue or an item has more than one description
+// rel for the same language, with an error wrapping ErrDuplicateDescription.
+// Otherwise the last such description is used.
+func RejectDuplicateDescriptions() ParseOption {
+	return func(o *parseOptions) {
+		o.rejectDuplicateDescr = true
+	}
+}
+
+// Strict returns a ParseOption enabling all of DisallowUnknownFields,
+// RejectDuplicateHrefs and RejectDuplicateDescriptions.
+func Strict() ParseOption {
+	return func(o *parseOptions) {
+		o.disallowUnknownField = true
+		o.rejectDuplicateHref = true
+		o.rejectDuplicateDescr = true
+	}
+}
+
+// VerifySignature returns a ParseOption that makes Parse verify the signature
+// of the catalogue using the given public key, failing if the catalogue is
+// unsigned or the signature is invalid.
+func VerifySignature(key crypto.PublicKey) ParseOption {
+	return func(o *parseOptions) {
+		o.catalogueKey = key
+	}
+}
+
+// VerifyItemSignatures returns a ParseOption that makes Parse verify the
+// signature of every item within the catalogue using the given public key,
+// failing if any item is unsigned or has an invalid signature.
+func VerifyItemSignatures(key crypto.PublicKey) ParseOption {
+	return func(o *parseOptions) {
+		o.itemKey = key
+	}
+}
+
+// Parse is a function that takes as input an io.Reader instance, which must
+// return a valid Hypercat document when read. This function then passes this
+// reader to a JSON Decoder, which attempts to parse and instantiate a valid
+// Hypercat struct. Any supplied options are applied to the parsed catalogue.
+//
+// Invalid documents are reported as a *ParseError identifying the item, if
+// any, and the byte offset at which the problem was found. Signature failures
+// are returned as is.
+func Parse(r io.Reader, opts ...ParseOption) (*Hypercat, error) {
+	options := parseOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var raw json.RawMessage
+
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			return nil, &ParseError{Index: -1, Offset: syntaxErr.Offset, Err: err}
+		}
+
+		return nil, err
+	}
+
+	cat := Hypercat{}
+
+	err = cat.decode(raw, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	cat.BaseURL = copyURL(options.baseURL)
+
+	if options.catalogueKey != nil {
+		err = cat.Verify(options.catalogueKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.itemKey != nil {
+		for i := range cat.Items {
+			err = cat.Items[i].Verify(options.itemKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &cat, nil
+}
+
+// decode populates the catalogue from its JSON encoding, applying the checks
+// enabled by the given options.
+func (h *Hypercat) decode(b []byte, options *parseOptions) error {
+	fields, fieldOffsets, err := scanObject(b)
+	if err != nil {
+		return catalogueError(0, err)
+	}
+
+	rawItems, offsets, err := scanArray(fields["items"])
+	if err != nil {
+		return catalogueError(fieldOffsets["items"], err)
+	}
+
+	var items Items
+
+	if rawItems != nil {
+		items = make(Items, len(rawItems))
+	}
+
+	for i, raw := range rawItems {
+		offsets[i] += fieldOffsets["items"]
+
+		err = json.Unmarshal(raw, &items[i])
+		if err != nil {
+			return &ParseError{Index: i, Href: peekHref(raw), Offset: offsets[i], Err: err}
+		}
+	}
+
+	var metadata Metadata
+
+	err = json.Unmarshal(orNull(fields["catalogue-metadata"]), &metadata)
+	if err != nil {
+		return catalogueError(fieldOffsets["catalogue-metadata"], err)
+	}
+
+	delete(fields, "items")
+	delete(fields, "catalogue-metadata")
+
+	h.Items = items
+	h.Extra = nil
+
+	if len(fields) > 0 {
+		h.Extra = fields
+	}
+
+	for _, rel := range metadata {
+		if lang, ok := descriptionLang(rel.Rel); ok {
+			storeDescription(&h.Description, &h.Descriptions, lang, rel.Val)
+		} else if rel.Rel == ContentTypeRel {
+			h.ContentType = rel.Val
+		} else {
+			h.Metadata = append(h.Metadata, rel)
+		}
+	}
+
+	if h.Description == "" && len(h.Descriptions) == 0 {
+		return catalogueError(fieldOffsets["catalogue-metadata"], ErrMissingDescriptionRel)
+	}
+
+	if h.ContentType == "" {
+		return catalogueError(fieldOffsets["catalogue-metadata"], ErrMissingContentTypeRel)
+	}
+
+	if options.disallowUnknownField {
+		err = unknownFieldError(h.Extra)
+		if err != nil {
+			return catalogueError(fieldOffsets[extraKeys(h.Extra)[0]], err)
+		}
+	}
+
+	if options.rejectDuplicateDescr {
+		err = duplicateDescriptionError(metadata)
+		if err != nil {
+			return catalogueError(fieldOffsets["catalogue-metadata"], err)
+		}
+	}
+
+	hrefs := map[string]bool{}
+
+	for i := range items {
+		item := &items[i]
+		err = nil
+
+		if options.disallowUnknownField {
+			err = unknownFieldError(item.Extra)
+		}
+
+		if err == nil && options.rejectDuplicateDescr {
+			err = duplicateItemDescriptionError(rawItems[i])
+		}
+
+		if err == nil && options.rejectDuplicateHref {
+			if hrefs[item.Href] {
+				err = ErrDuplicateHref
+			}
+
+			hrefs[item.Href] = true
+		}
+
+		if err != nil {
+			return &ParseError{Index: i, Href: item.Href, Offset: offsets[i], Err: err}
+		}
+	}
+
+	return nil
+}
+
+// scanObject splits a JSON object into the raw encoding of each of its fields,
+// along with the byte offset at which each field's value starts. A null value
+// returns no fields.
+func scanObject(b []byte) (map[string]json.RawMessage, map[string]int64, error) {
+	fields := map[string]json.RawMessage{}
+	offsets := map[string]int64{}
+
+	dec, err := openJSON(b, '{', &fields)
+	if dec == nil || err != nil {
+		return fields, offsets, err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := tok.(string)
+
+		var raw json.RawMessage
+
+		err = dec.Decode(&raw)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fields[key] = raw
+		offsets[key] = dec.InputOffset() - int64(len(raw))
+	}
+
+	_, err = dec.Token()
+
+	return fields, offsets, err
+}
+
+// scanArray splits a JSON array into the raw encoding of each of its elements,
+// along with the byte offset at which each element starts. A null or absent
+// value returns a nil slice.
+func scanArray(b []byte) ([]json.RawMessage, []int64, error) {
+	var elems []json.RawMessage
+	var offsets []int64
+
+	dec, err := openJSON(b, '[', &elems)
+	if dec == nil || err != nil {
+		return nil, nil, err
+	}
+
+	elems = []json.RawMessage{}
+
+	for dec.More() {
+		var raw json.RawMessage
+
+		err = dec.Decode(&raw)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		elems = append(elems, raw)
+		offsets = append(offsets, dec.InputOffset()-int64(len(raw)))
+	}
+
+	_, err = dec.Token()
+
+	return elems, offsets, err
+}
+
+// openJSON returns a Decoder positioned within the JSON object or array
+// opened by delim. If b is absent or holds some other value, it is instead
+// unmarshalled into v so that encoding/json reports any mismatched type in the
+// usual way, and a nil Decoder is returned.
+func openJSON(b []byte, delim json.Delim, v interface{}) (*json.Decoder, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok != delim {
+		return nil, json.Unmarshal(b, v)
+	}
+
+	return dec, nil
+}
+
+// orNull returns the given raw JSON, or null if it is absent.
+func orNull(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return json.RawMessage("null")
+	}
+
+	return raw
+}
+
+// catalogueError returns a ParseError for a failure within the catalogue
+// rather than one of its items. The position of a JSON error is used in
+// preference to the given offset of the field being decoded.
+func catalogueError(offset int64, err error) *ParseError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	if errors.As(err, &syntaxErr) {
+		offset += syntaxErr.Offset
+	} else if errors.As(err, &typeErr) {
+		offset += typeErr.Offset
+	}
+
+	return &ParseError{Index: -1, Offset: offset, Err: err}
+}
+
+// peekHref returns the href of an item that failed to decode, or "" if it
+// can't be determined.
+func peekHref(raw json.RawMessage) string {
+	var item struct {
+		Href string `json:"href"`
+	}
+
+	json.Unmarshal(raw, &item)
+
+	return item.Href
+}
+
+// duplicateDescriptionError returns an error if the given metadata contains
+// more than one description rel for the same language.
+func duplicateDescriptionError(metadata Metadata) error {
+	seen := map[string]bool{}
+
+	for _, rel := range metadata {
+		lang, ok := descriptionLang(rel.Rel)
+		if !ok {
+			continue
+		}
+
+		lang = strings.ToLower(lang)
+
+		if seen[lang] {
+			return fmt.Errorf("%w: %q", ErrDuplicateDescription, rel.Rel)
+		}
+
+		seen[lang] = true
+	}
+
+	return nil
+}
+
+// duplicateItemDescriptionError returns an error if the encoded item contains
+// more than one description rel for the same language. The item metadata is
+// decoded again as Item collapses descriptions into a single value.
+func duplicateItemDescriptionError(raw json.RawMessage) error {
+	var item struct {
+		Metadata Metadata `json:"item-metadata"`
+	}
+
+	err := json.Unmarshal(raw, &item)
+	if err != nil {
+		return err
+	}
+
+	return duplicateDescriptionError(item.Metadata)
+}
@@ -0,0 +1,121 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const catalogueMetadata = `"catalogue-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Catalogue"},{"rel":"urn:X-hypercat:rels:isContentType","val":"application/vnd.hypercat.catalogue+json"}]`
+
+// testDocument returns a catalogue document containing the given encoded
+// items.
+func testDocument(items ...string) string {
+	return `{"items": [` + strings.Join(items, ", ") + `], ` + catalogueMetadata + `}`
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	foo := `{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Foo"}]}`
+	bar := `{"href":"/bar","item-metadata":[]}`
+	anonymous := `{"item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Anonymous"}]}`
+	invalid := `{"href":"/baz","item-metadata":{}}`
+
+	var tests = []struct {
+		document string
+		index    int
+		href     string
+		offset   int
+		err      error
+	}{
+		{testDocument(foo, bar), 1, "/bar", strings.Index(testDocument(foo, bar), bar), ErrMissingDescriptionRel},
+		{testDocument(foo, anonymous), 1, "", strings.Index(testDocument(foo, anonymous), anonymous), ErrMissingHref},
+		{testDocument(invalid), 0, "/baz", strings.Index(testDocument(invalid), invalid), nil},
+		{`{"items":[],"catalogue-metadata":[]}`, -1, "", len(`{"items":[],"catalogue-metadata":`), ErrMissingDescriptionRel},
+		// encoding/json reports the offset following the offending byte.
+		{`{"items":{},` + catalogueMetadata + `}`, -1, "", len(`{"items":{`), nil},
+		{`{"items":[]` + catalogueMetadata + `}`, -1, "", len(`{"items":[]"`), nil},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.document))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse error, expected a ParseError, got '%v'", err)
+			continue
+		}
+
+		if parseErr.Index != test.index || parseErr.Href != test.href || parseErr.Offset != int64(test.offset) {
+			t.Errorf("ParseError position, expected '%v %q %v', got '%v %q %v'", test.index, test.href, test.offset, parseErr.Index, parseErr.Href, parseErr.Offset)
+		}
+
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("ParseError cause, expected '%v', got '%v'", test.err, parseErr.Err)
+		}
+	}
+}
+
+func TestUnmarshalParseError(t *testing.T) {
+	document := testDocument(`{"item-metadata":[]}`)
+
+	cat := Hypercat{}
+
+	err := json.Unmarshal([]byte(document), &cat)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 0 || !errors.Is(err, ErrMissingHref) {
+		t.Errorf("Unmarshal error, expected a ParseError for item 0, got '%v'", err)
+	}
+
+	expected := `item 0 (offset 11): "href" is a mandatory attribute`
+	if err.Error() != expected {
+		t.Errorf("ParseError message, expected '%v', got '%v'", expected, err.Error())
+	}
+}
+
+func TestStrictParse(t *testing.T) {
+	foo := `{"href":"/foo","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Foo"}]}`
+	described := `{"href":"/bar","item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Bar"},{"rel":"urn:X-hypercat:rels:hasDescription:EN","val":"Again"}]}`
+	unknown := `{"href":"/bar","vendor:flag":true,"item-metadata":[{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Bar"}]}`
+	catalogue := strings.Replace(testDocument(foo), `"val":"Catalogue"}`, `"val":"Catalogue"},{"rel":"urn:X-hypercat:rels:hasDescription:en","val":"Again"}`, 1)
+
+	var tests = []struct {
+		document string
+		option   ParseOption
+		index    int
+		href     string
+		err      error
+	}{
+		{testDocument(foo, foo), RejectDuplicateHrefs(), 1, "/foo", ErrDuplicateHref},
+		{testDocument(foo, described), RejectDuplicateDescriptions(), 1, "/bar", ErrDuplicateDescription},
+		{catalogue, RejectDuplicateDescriptions(), -1, "", ErrDuplicateDescription},
+		{testDocument(foo, unknown), DisallowUnknownFields(), 1, "/bar", ErrUnknownField},
+		{testDocument(foo, foo), Strict(), 1, "/foo", ErrDuplicateHref},
+		{testDocument(described), Strict(), 0, "/bar", ErrDuplicateDescription},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.document))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		_, err = Parse(strings.NewReader(test.document), test.option)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, test.err) {
+			t.Errorf("Strict parse error, expected '%v', got '%v'", test.err, err)
+			continue
+		}
+
+		if parseErr.Index != test.index || parseErr.Href != test.href {
+			t.Errorf("ParseError position, expected '%v %q', got '%v %q'", test.index, test.href, parseErr.Index, parseErr.Href)
+		}
+	}
+
+	_, err := Parse(strings.NewReader(testDocument(foo)), Strict())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package hypercat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SearchCapabilities is the set of search modes supported by a catalogue
+// server, keyed by the values of SupportsSearchRel identifying them.
+type SearchCapabilities map[string]bool
+
+// Capabilities returns the search modes advertised by the catalogue through
+// its SupportsSearchRel values. Values identifying modes unknown to this
+// package are included, but can't be used to build a Query.
+func (h *Hypercat) Capabilities() SearchCapabilities {
+	caps := SearchCapabilities{}
+
+	for _, mode := range h.Vals(SupportsSearchRel) {
+		caps[mode] = true
+	}
+
+	return caps
+}
+
+// Supports reports whether the given search mode is supported.
+func (c SearchCapabilities) Supports(mode string) bool {
+	return c[mode]
+}
+
+// Modes returns the supported search modes implemented by this package, in
+// the order returned by AllSearchModes.
+func (c SearchCapabilities) Modes() []string {
+	modes := []string{}
+
+	for _, mode := range AllSearchModes() {
+		if c[mode] {
+			modes = append(modes, mode)
+		}
+	}
+
+	return modes
+}
+
+// Query is a single search of a catalogue, using one of the search modes
+// defined by the Hypercat specification.
+type Query struct {
+	mode   string
+	params url.Values
+}
+
+// SimpleQuery is a constructor function that creates and returns a simple
+// search for items with the given href, and with a rel and value matching rel
+// and val. Empty arguments match anything.
+func SimpleQuery(href, rel, val string) *Query {
+	return newQuery(SimpleSearchVal, "href", href, "rel", rel, "val", val)
+}
+
+// GeoBoundQuery is a constructor function that creates and returns a search
+// for items located within the given bounding box.
+func GeoBoundQuery(minLong, minLat, maxLong, maxLat float64) *Query {
+	q := &Query{mode: GeoBoundSearchVal, params: url.Values{}}
+
+	for i, bound := range []float64{minLong, minLat, maxLong, maxLat} {
+		q.params.Set(searchParams[GeoBoundSearchVal][i], formatFloat(bound))
+	}
+
+	return q
+}
+
+// LexRangeQuery is a constructor function that creates and returns a search
+// for items with a value of the given rel lying lexicographically between min
+// inclusive and max exclusive. Empty bounds are unbounded.
+func LexRangeQuery(rel, min, max string) *Query {
+	q := newQuery(LexicographicSearchVal, "lexrange-min", min, "lexrange-max", max)
+	q.params.Set("lexrange-rel", rel)
+
+	return q
+}
+
+// PrefixQuery is a constructor function that creates and returns a search for
+// items whose href starts with href, and with a rel matching rel whose value
+// starts with val. Empty arguments match anything.
+func PrefixQuery(href, rel, val string) *Query {
+	return newQuery(PrefixSearchVal, "prefix-href", href, "prefix-rel", rel, "prefix-val", val)
+}
+
+// newQuery returns a query using the given mode, with parameters set from
+// pairs of names and values. Parameters with empty values are omitted.
+func newQuery(mode string, pairs ...string) *Query {
+	q := &Query{mode: mode, params: url.Values{}}
+
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			q.params.Set(pairs[i], pairs[i+1])
+		}
+	}
+
+	return q
+}
+
+// Mode returns the value of SupportsSearchRel identifying the query's search
+// mode.
+func (q *Query) Mode() string {
+	return q.mode
+}
+
+// Values returns a copy of the query parameters expressing the query, as
+// accepted by Hypercat.Search.
+func (q *Query) Values() url.Values {
+	values := url.Values{}
+
+	for key, vals := range q.params {
+		values[key] = append([]string(nil), vals...)
+	}
+
+	return values
+}
+
+// Encode returns the query encoded as a URL query string.
+func (q *Query) Encode() string {
+	return q.params.Encode()
+}
+
+// Searcher searches a remote catalogue, allowing only the search modes it
+// supports.
+type Searcher struct {
+	Client       *Client
+	URL          string             // URL of the catalogue being searched.
+	Capabilities SearchCapabilities // Search modes supported by the catalogue.
+}
+
+// Searcher retrieves the catalogue at the given URL and returns a Searcher
+// for it, limited to the search modes advertised by the catalogue.
+func (c *Client) Searcher(ctx context.Context, rawurl string) (*Searcher, error) {
+	cat, err := c.Fetch(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Searcher{
+		Client:       c,
+		URL:          rawurl,
+		Capabilities: cat.Capabilities(),
+	}, nil
+}
+
+// SearchURL returns the URL used to perform the given query. Returns an error
+// wrapping ErrUnsupportedSearch if the catalogue doesn't support the query's
+// mode.
+func (s *Searcher) SearchURL(q *Query) (string, error) {
+	err := s.check(q.mode)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+
+	for key, vals := range q.params {
+		query[key] = vals
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Search performs the given query, returning a catalogue containing every
+// matching item, retrieved from all pages of the results. Returns an error
+// wrapping ErrUnsupportedSearch if the catalogue doesn't support the query's
+// mode.
+func (s *Searcher) Search(ctx context.Context, q *Query) (*Hypercat, error) {
+	rawurl, err := s.SearchURL(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Client.FetchAll(ctx, rawurl)
+}
+
+// MultiSearchBody returns the body of a multi-search request combining the
+// given queries, returning the items matching all of them if intersection is
+// true or any of them otherwise. Returns an error wrapping
+// ErrUnsupportedSearch if the catalogue doesn't support multi-search, or the
+// mode of any query.
+func (s *Searcher) MultiSearchBody(intersection bool, queries ...*Query) (*MultiSearch, error) {
+	err := s.check(MultiSearchVal)
+	if err != nil {
+		return nil, err
+	}
+
+	multi := &MultiSearch{
+		Queries:      make([]string, len(queries)),
+		Intersection: intersection,
+	}
+
+	for i, q := range queries {
+		err = s.check(q.mode)
+		if err != nil {
+			return nil, err
+		}
+
+		multi.Queries[i] = q.Encode()
+	}
+
+	return multi, nil
+}
+
+// MultiSearch performs a multi-search combining the given queries, as
+// described by MultiSearchBody. Only the first page of the results is
+// returned if the server paginates them.
+func (s *Searcher) MultiSearch(ctx context.Context, intersection bool, queries ...*Query) (*Hypercat, error) {
+	multi, err := s.MultiSearchBody(intersection, queries...)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(multi)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	query.Set("multi", "")
+	u.RawQuery = query.Encode()
+
+	req, err := s.Client.newRequest(ctx, "POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return s.Client.parseResponse(req, http.StatusOK)
+}
+
+// check returns an error wrapping ErrUnsupportedSearch if the catalogue
+// doesn't support the given search mode.
+func (s *Searcher) check(mode string) error {
+	if !s.Capabilities.Supports(mode) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedSearch, mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package hypercat
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+	cat.AddRel(SupportsSearchRel, MultiSearchVal)
+	cat.AddRel(SupportsSearchRel, SimpleSearchVal)
+	cat.AddRel(SupportsSearchRel, "urn:example:search")
+
+	caps := cat.Capabilities()
+
+	if !caps.Supports("urn:example:search") || caps.Supports(PrefixSearchVal) {
+		t.Errorf("Capabilities error, got '%v'", caps)
+	}
+
+	expected := []string{SimpleSearchVal, MultiSearchVal}
+
+	if got := caps.Modes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Capabilities modes error, expected '%v', got '%v'", expected, got)
+	}
+}
+
+func TestQueryEncode(t *testing.T) {
+	var testcases = []struct {
+		query    *Query
+		mode     string
+		expected string
+	}{
+		{SimpleQuery("", "urn:example:name", "apple"), SimpleSearchVal, "rel=urn%3Aexample%3Aname&val=apple"},
+		{SimpleQuery("/foo", "", ""), SimpleSearchVal, "href=%2Ffoo"},
+		{GeoBoundQuery(-1, 50.5, 1, 52), GeoBoundSearchVal, "geobound-maxlat=52&geobound-maxlong=1&geobound-minlat=50.5&geobound-minlong=-1"},
+		{LexRangeQuery("urn:example:name", "a", ""), LexicographicSearchVal, "lexrange-min=a&lexrange-rel=urn%3Aexample%3Aname"},
+		{PrefixQuery("/sensors/", "", ""), PrefixSearchVal, "prefix-href=%2Fsensors%2F"},
+	}
+
+	for _, testcase := range testcases {
+		if testcase.query.Mode() != testcase.mode {
+			t.Errorf("Query mode error, expected '%v', got '%v'", testcase.mode, testcase.query.Mode())
+		}
+
+		if got := testcase.query.Encode(); got != testcase.expected {
+			t.Errorf("Query encoding error, expected '%v', got '%v'", testcase.expected, got)
+		}
+
+		mode, err := searchMode(testcase.query.Values())
+		if err != nil || mode != testcase.mode {
+			t.Errorf("Query values error, expected mode '%v', got '%v' (%v)", testcase.mode, mode, err)
+		}
+	}
+}
+
+func TestSearcher(t *testing.T) {
+	handler := NewHandler(searchCatalogue())
+	handler.SearchModes = []string{SimpleSearchVal, PrefixSearchVal, MultiSearchVal}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	searcher, err := NewClient().Searcher(ctx, server.URL+"/cat?limit=1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := searcher.Capabilities.Modes(); !reflect.DeepEqual(got, handler.SearchModes) {
+		t.Errorf("Searcher capabilities error, expected '%v', got '%v'", handler.SearchModes, got)
+	}
+
+	rawurl, _ := searcher.SearchURL(PrefixQuery("/sensors/", "", ""))
+
+	if expected := server.URL + "/cat?limit=1&prefix-href=%2Fsensors%2F"; rawurl != expected {
+		t.Errorf("Searcher URL error, expected '%v', got '%v'", expected, rawurl)
+	}
+
+	cat, err := searcher.Search(ctx, PrefixQuery("/sensors/", "", ""))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, []string{"/sensors/london", "/sensors/paris"}) {
+		t.Errorf("Searcher search error, got '%v'", got)
+	}
+
+	// Only the first page of multi-search results is returned.
+	cat, err = searcher.MultiSearch(ctx, false, SimpleQuery("", "", "apple"), SimpleQuery("", "", "cherry"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := hrefs(cat.Items); !reflect.DeepEqual(got, []string{"/sensors/london"}) {
+		t.Errorf("Searcher multi-search error, got '%v'", got)
+	}
+
+	_, err = searcher.Search(ctx, LexRangeQuery("urn:example:name", "a", "b"))
+	if !errors.Is(err, ErrUnsupportedSearch) {
+		t.Errorf("Searcher search error, expected '%v', got '%v'", ErrUnsupportedSearch, err)
+	}
+
+	_, err = searcher.MultiSearchBody(true, SimpleQuery("/foo", "", ""), GeoBoundQuery(0, 0, 1, 1))
+	if !errors.Is(err, ErrUnsupportedSearch) {
+		t.Errorf("Searcher multi-search error, expected '%v', got '%v'", ErrUnsupportedSearch, err)
+	}
+
+	delete(searcher.Capabilities, MultiSearchVal)
+
+	_, err = searcher.MultiSearch(ctx, false, SimpleQuery("/foo", "", ""))
+	if !errors.Is(err, ErrUnsupportedSearch) {
+		t.Errorf("Searcher multi-search error, expected '%v', got '%v'", ErrUnsupportedSearch, err)
+	}
+}
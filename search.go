@@ -0,0 +1,287 @@
+package hypercat
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// AllSearchModes returns the values of SupportsSearchRel identifying each of
+// the search modes implemented by Hypercat.Search and Hypercat.MultiSearch.
+func AllSearchModes() []string {
+	return []string{SimpleSearchVal, GeoBoundSearchVal, LexicographicSearchVal, PrefixSearchVal, MultiSearchVal}
+}
+
+// MultiSearch is the body of a multi-search request, which combines the
+// results of several searches. Each query is a query string in the form
+// accepted by Hypercat.Search, such as "rel=...&val=...".
+type MultiSearch struct {
+	Queries      []string `json:"query"`
+	Intersection bool     `json:"intersection"` // Whether to return the items matching every query, rather than any query.
+}
+
+// searchParams lists the query parameters of each search mode. Pagination
+// parameters belong to no mode and are ignored when searching.
+var searchParams = map[string][]string{
+	SimpleSearchVal:        {"href", "rel", "val"},
+	GeoBoundSearchVal:      {"geobound-minlong", "geobound-minlat", "geobound-maxlong", "geobound-maxlat"},
+	LexicographicSearchVal: {"lexrange-rel", "lexrange-min", "lexrange-max"},
+	PrefixSearchVal:        {"prefix-href", "prefix-rel", "prefix-val"},
+}
+
+// matcher reports whether the given item matches a search.
+type matcher func(item *Item) bool
+
+// Search returns a catalogue containing the items of this catalogue that match
+// a search expressed as query parameters, in the form defined by the Hypercat
+// specification for each search mode:
+//
+//	simple:   href, rel, val
+//	geobound: geobound-minlong, geobound-minlat, geobound-maxlong, geobound-maxlat
+//	lexrange: lexrange-rel, lexrange-min, lexrange-max
+//	prefix:   prefix-href, prefix-rel, prefix-val
+//
+// Parameters of a single mode may be used in each search, and other
+// parameters are ignored. A query without search parameters matches every
+// item. Returns an error wrapping ErrInvalidSearch if the query is malformed.
+//
+// Geobound searches are answered by the catalogue's SpatialIndex, if one is
+// attached.
+func (h *Hypercat) Search(query url.Values) (*Hypercat, error) {
+	if idx := h.spatialIndex(); idx != nil {
+		mode, err := searchMode(query)
+		if err != nil {
+			return nil, err
+		}
+
+		if mode == GeoBoundSearchVal {
+			bounds, err := parseGeoBound(query)
+			if err != nil {
+				return nil, err
+			}
+
+			return idx.Within(h, bounds[0], bounds[1], bounds[2], bounds[3]), nil
+		}
+	}
+
+	match, err := parseSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.withItems(h.matching(match)), nil
+}
+
+// MultiSearch returns a catalogue containing the items of this catalogue that
+// match either all or any of the given searches. Returns an error wrapping
+// ErrInvalidSearch if any query is malformed.
+func (h *Hypercat) MultiSearch(multi *MultiSearch) (*Hypercat, error) {
+	if len(multi.Queries) == 0 {
+		return nil, fmt.Errorf("%w: no queries", ErrInvalidSearch)
+	}
+
+	matchers := make([]matcher, len(multi.Queries))
+
+	for i, raw := range multi.Queries {
+		query, err := url.ParseQuery(strings.TrimPrefix(raw, "?"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+		}
+
+		matchers[i], err = parseSearch(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return h.withItems(h.matching(func(item *Item) bool {
+		for _, match := range matchers {
+			matched := match(item)
+
+			if matched && !multi.Intersection {
+				return true
+			}
+
+			if !matched && multi.Intersection {
+				return false
+			}
+		}
+
+		return multi.Intersection
+	})), nil
+}
+
+// matching returns copies of the items matched by the given matcher.
+func (h *Hypercat) matching(match matcher) Items {
+	items := Items{}
+
+	for i := range h.Items {
+		if match(&h.Items[i]) {
+			items = append(items, *h.Items[i].clone())
+		}
+	}
+
+	return items
+}
+
+// searchMode returns the search mode whose parameters appear in the query, or
+// "" if there are none. Returns an error if parameters of several modes are
+// mixed.
+func searchMode(query url.Values) (string, error) {
+	found := ""
+
+	for _, mode := range AllSearchModes() {
+		for _, param := range searchParams[mode] {
+			if _, ok := query[param]; !ok {
+				continue
+			}
+
+			if found != "" && found != mode {
+				return "", fmt.Errorf("%w: parameters of several search modes", ErrInvalidSearch)
+			}
+
+			found = mode
+		}
+	}
+
+	return found, nil
+}
+
+// parseSearch returns a matcher for the search expressed by the query.
+func parseSearch(query url.Values) (matcher, error) {
+	mode, err := searchMode(query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case SimpleSearchVal:
+		return simpleMatcher(query), nil
+	case GeoBoundSearchVal:
+		return geoBoundMatcher(query)
+	case LexicographicSearchVal:
+		return lexRangeMatcher(query)
+	case PrefixSearchVal:
+		return prefixMatcher(query), nil
+	default:
+		return func(item *Item) bool { return true }, nil
+	}
+}
+
+// simpleMatcher matches items with the given href, and with a Rel whose key
+// and value match rel and val. Absent parameters match anything.
+func simpleMatcher(query url.Values) matcher {
+	href, hasHref := query["href"]
+	rel, hasRel := query["rel"]
+	val, hasVal := query["val"]
+
+	return func(item *Item) bool {
+		if hasHref && item.Href != href[0] {
+			return false
+		}
+
+		if !hasRel && !hasVal {
+			return true
+		}
+
+		return anyRel(item, func(r Rel) bool {
+			return (!hasRel || r.Rel == rel[0]) && (!hasVal || r.Val == val[0])
+		})
+	}
+}
+
+// parseGeoBound returns the minimum longitude, minimum latitude, maximum
+// longitude and maximum latitude of a geobound search.
+func parseGeoBound(query url.Values) ([]float64, error) {
+	bounds := make([]float64, 4)
+
+	for i, param := range searchParams[GeoBoundSearchVal] {
+		f, err := strconv.ParseFloat(query.Get(param), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidSearch, param)
+		}
+
+		bounds[i] = f
+	}
+
+	return bounds, nil
+}
+
+// geoBoundMatcher matches items whose latitude and longitude lie within the
+// given bounding box, inclusive of its edges.
+func geoBoundMatcher(query url.Values) (matcher, error) {
+	bounds, err := parseGeoBound(query)
+	if err != nil {
+		return nil, err
+	}
+
+	minLong, minLat, maxLong, maxLat := bounds[0], bounds[1], bounds[2], bounds[3]
+
+	return func(item *Item) bool {
+		lat, err := item.Float(LatitudeRel)
+		if err != nil {
+			return false
+		}
+
+		long, err := item.Float(LongitudeRel)
+		if err != nil {
+			return false
+		}
+
+		return lat >= minLat && lat <= maxLat && long >= minLong && long <= maxLong
+	}, nil
+}
+
+// lexRangeMatcher matches items with a value of the given rel lying
+// lexicographically within [min, max). Absent bounds are unbounded.
+func lexRangeMatcher(query url.Values) (matcher, error) {
+	rel := query.Get("lexrange-rel")
+	if rel == "" {
+		return nil, fmt.Errorf("%w: lexrange-rel is required", ErrInvalidSearch)
+	}
+
+	lower, hasLower := query["lexrange-min"]
+	upper, hasUpper := query["lexrange-max"]
+
+	return func(item *Item) bool {
+		return anyRel(item, func(r Rel) bool {
+			return r.Rel == rel && (!hasLower || r.Val >= lower[0]) && (!hasUpper || r.Val < upper[0])
+		})
+	}, nil
+}
+
+// prefixMatcher matches items whose href starts with prefix-href, and with a
+// Rel matching prefix-rel whose value starts with prefix-val. Absent
+// parameters match anything.
+func prefixMatcher(query url.Values) matcher {
+	href := query.Get("prefix-href")
+	rel, hasRel := query["prefix-rel"]
+	val, hasVal := query["prefix-val"]
+
+	return func(item *Item) bool {
+		if !strings.HasPrefix(item.Href, href) {
+			return false
+		}
+
+		if !hasRel && !hasVal {
+			return true
+		}
+
+		return anyRel(item, func(r Rel) bool {
+			return (!hasRel || r.Rel == rel[0]) && (!hasVal || strings.HasPrefix(r.Val, val[0]))
+		})
+	}
+}
+
+// anyRel reports whether any Rel of the item, including its descriptions,
+// satisfies the predicate.
+func anyRel(item *Item, pred func(Rel) bool) bool {
+	for _, rel := range item.allMetadata() {
+		if pred(rel) {
+			return true
+		}
+	}
+
+	return false
+}
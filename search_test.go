@@ -0,0 +1,129 @@
+package hypercat
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// searchCatalogue returns a catalogue of items to search.
+func searchCatalogue() *Hypercat {
+	cat := NewHypercat("Catalogue description")
+
+	london := NewItem("/sensors/london", "London sensor")
+	london.SetFloat(LatitudeRel, 51.5)
+	london.SetFloat(LongitudeRel, -0.125)
+	london.AddRel("urn:example:name", "apple")
+
+	paris := NewItem("/sensors/paris", "Paris sensor")
+	paris.SetFloat(LatitudeRel, 48.85)
+	paris.SetFloat(LongitudeRel, 2.35)
+	paris.AddRel("urn:example:name", "banana")
+
+	parks := NewItem("/parks", "Parks")
+	parks.AddRel(ContentTypeRel, HypercatMediaType)
+	parks.AddRel("urn:example:name", "cherry")
+
+	cat.AddItem(london)
+	cat.AddItem(paris)
+	cat.AddItem(parks)
+
+	return cat
+}
+
+func TestSearch(t *testing.T) {
+	var testcases = []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"/sensors/london", "/sensors/paris", "/parks"}},
+		{"offset=1&limit=1", []string{"/sensors/london", "/sensors/paris", "/parks"}},
+		{"href=/parks", []string{"/parks"}},
+		{"rel=" + url.QueryEscape(ContentTypeRel), []string{"/parks"}},
+		{"val=banana", []string{"/sensors/paris"}},
+		{"rel=urn:example:name&val=apple", []string{"/sensors/london"}},
+		{"rel=" + url.QueryEscape(DescriptionRel) + "&val=Parks", []string{"/parks"}},
+		{"href=/parks&val=apple", []string{}},
+		{"geobound-minlong=-1&geobound-minlat=50&geobound-maxlong=1&geobound-maxlat=52", []string{"/sensors/london"}},
+		{"geobound-minlong=-1&geobound-minlat=40&geobound-maxlong=3&geobound-maxlat=52", []string{"/sensors/london", "/sensors/paris"}},
+		{"lexrange-rel=urn:example:name&lexrange-min=b", []string{"/sensors/paris", "/parks"}},
+		{"lexrange-rel=urn:example:name&lexrange-min=apple&lexrange-max=banana", []string{"/sensors/london"}},
+		{"prefix-href=/sensors/", []string{"/sensors/london", "/sensors/paris"}},
+		{"prefix-rel=urn:example:name&prefix-val=ch", []string{"/parks"}},
+	}
+
+	cat := searchCatalogue()
+
+	for _, testcase := range testcases {
+		query, _ := url.ParseQuery(testcase.query)
+
+		results, err := cat.Search(query)
+		if err != nil {
+			t.Errorf("Unexpected error for '%v': %v", testcase.query, err)
+			continue
+		}
+
+		if got := hrefs(results.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("Search error for '%v', expected '%v', got '%v'", testcase.query, testcase.expected, got)
+		}
+
+		if results.Description != cat.Description {
+			t.Errorf("Search description error, expected '%v', got '%v'", cat.Description, results.Description)
+		}
+	}
+
+	if len(cat.Items) != 3 {
+		t.Errorf("Search should not modify the catalogue")
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	var testcases = []string{
+		"href=/parks&prefix-href=/",
+		"geobound-minlong=-1&geobound-minlat=50&geobound-maxlong=1",
+		"geobound-minlong=west&geobound-minlat=50&geobound-maxlong=1&geobound-maxlat=52",
+		"lexrange-min=a",
+	}
+
+	for _, testcase := range testcases {
+		query, _ := url.ParseQuery(testcase)
+
+		_, err := searchCatalogue().Search(query)
+		if !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("Search error for '%v', expected '%v', got '%v'", testcase, ErrInvalidSearch, err)
+		}
+	}
+}
+
+func TestMultiSearch(t *testing.T) {
+	var testcases = []struct {
+		multi    MultiSearch
+		expected []string
+	}{
+		{MultiSearch{Queries: []string{"prefix-href=/sensors/", "val=cherry"}}, []string{"/sensors/london", "/sensors/paris", "/parks"}},
+		{MultiSearch{Queries: []string{"prefix-href=/sensors/", "?val=banana"}, Intersection: true}, []string{"/sensors/paris"}},
+		{MultiSearch{Queries: []string{"href=/parks", "val=apple"}, Intersection: true}, []string{}},
+	}
+
+	cat := searchCatalogue()
+
+	for _, testcase := range testcases {
+		results, err := cat.MultiSearch(&testcase.multi)
+		if err != nil {
+			t.Errorf("Unexpected error for '%v': %v", testcase.multi, err)
+			continue
+		}
+
+		if got := hrefs(results.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("MultiSearch error for '%v', expected '%v', got '%v'", testcase.multi, testcase.expected, got)
+		}
+	}
+
+	for _, multi := range []MultiSearch{{}, {Queries: []string{"lexrange-min=a"}}, {Queries: []string{"%zz"}}} {
+		_, err := cat.MultiSearch(&multi)
+		if !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("MultiSearch error for '%v', expected '%v', got '%v'", multi, ErrInvalidSearch, err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package hypercat
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	_ "crypto/sha256" // register hash functions used by ECDSA signatures
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// jwsHeader is the protected header of the JWS signatures produced by this
+// package.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+}
+
+// Sign signs the canonical serialization of the catalogue using the given
+// private key, which must be an ed25519.PrivateKey or *ecdsa.PrivateKey, and
+// stores the resulting detached JWS (RFC 7515, appendix F) as the value of the
+// catalogue's SignatureRel, replacing any existing signature.
+//
+// The catalogue's own SignatureRel is excluded from the signed content, but
+// the signatures of any signed items are included.
+func (h *Hypercat) Sign(key crypto.PrivateKey) error {
+	payload, err := h.signedContent()
+	if err != nil {
+		return err
+	}
+
+	signature, err := signJWS(payload, key)
+	if err != nil {
+		return err
+	}
+
+	h.Metadata.Set(SignatureRel, signature)
+
+	return nil
+}
+
+// Verify checks the catalogue's SignatureRel against its canonical
+// serialization using the given public key, which must be an
+// ed25519.PublicKey or *ecdsa.PublicKey. Returns ErrMissingSignature if the
+// catalogue isn't signed, or ErrInvalidSignature if verification fails.
+func (h *Hypercat) Verify(key crypto.PublicKey) error {
+	signature, err := signatureOf(h.Metadata)
+	if err != nil {
+		return err
+	}
+
+	payload, err := h.signedContent()
+	if err != nil {
+		return err
+	}
+
+	return verifyJWS(signature, payload, key)
+}
+
+// Sign signs the canonical serialization of the item using the given private
+// key, storing the resulting detached JWS as the value of the item's
+// SignatureRel. See Hypercat.Sign for details.
+func (item *Item) Sign(key crypto.PrivateKey) error {
+	payload, err := item.signedContent()
+	if err != nil {
+		return err
+	}
+
+	signature, err := signJWS(payload, key)
+	if err != nil {
+		return err
+	}
+
+	item.Metadata.Set(SignatureRel, signature)
+
+	return nil
+}
+
+// Verify checks the item's SignatureRel against its canonical serialization
+// using the given public key. See Hypercat.Verify for details.
+func (item *Item) Verify(key crypto.PublicKey) error {
+	signature, err := signatureOf(item.Metadata)
+	if err != nil {
+		return err
+	}
+
+	payload, err := item.signedContent()
+	if err != nil {
+		return err
+	}
+
+	return verifyJWS(signature, payload, key)
+}
+
+// signedContent returns the canonical serialization of the catalogue without
+// its SignatureRel.
+func (h *Hypercat) signedContent() ([]byte, error) {
+	c := h.withItems(h.Items)
+	c.Metadata.Remove(SignatureRel)
+
+	return c.CanonicalJSON()
+}
+
+// signedContent returns the canonical serialization of the item without its
+// SignatureRel.
+func (item *Item) signedContent() ([]byte, error) {
+	c := item.clone()
+	c.Metadata.Remove(SignatureRel)
+
+	return c.CanonicalJSON()
+}
+
+// signatureOf returns the single signature contained within some metadata.
+func signatureOf(m Metadata) (string, error) {
+	vals := m.Vals(SignatureRel)
+
+	switch len(vals) {
+	case 0:
+		return "", ErrMissingSignature
+	case 1:
+		return vals[0], nil
+	default:
+		return "", ErrInvalidSignature
+	}
+}
+
+// signJWS returns a detached compact JWS signing the given payload.
+func signJWS(payload []byte, key crypto.PrivateKey) (string, error) {
+	var alg string
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		alg = "EdDSA"
+	case *ecdsa.PrivateKey:
+		alg = ecdsaAlg(k.Curve)
+	}
+
+	if alg == "" {
+		return "", ErrUnsupportedKey
+	}
+
+	header, err := json.Marshal(jwsHeader{Alg: alg})
+	if err != nil {
+		return "", err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	input := []byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload))
+
+	var sig []byte
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, input)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest(alg, input))
+		if err != nil {
+			return "", err
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+	}
+
+	return protected + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// verifyJWS checks that the given detached compact JWS signs the given
+// payload.
+func verifyJWS(signature string, payload []byte, key crypto.PublicKey) error {
+	parts := strings.Split(signature, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return ErrInvalidSignature
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	header := jwsHeader{}
+
+	err = json.Unmarshal(b, &header)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	input := []byte(parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload))
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if header.Alg == "EdDSA" && ed25519.Verify(k, input, sig) {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+
+		if header.Alg == ecdsaAlg(k.Curve) && len(sig) == 2*size {
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+
+			if ecdsa.Verify(k, digest(header.Alg, input), r, s) {
+				return nil
+			}
+		}
+	default:
+		return ErrUnsupportedKey
+	}
+
+	return ErrInvalidSignature
+}
+
+// ecdsaAlg returns the JWS algorithm name for ECDSA signatures using the
+// given curve, or "" if the curve isn't supported.
+func ecdsaAlg(curve elliptic.Curve) string {
+	switch curve {
+	case elliptic.P256():
+		return "ES256"
+	case elliptic.P384():
+		return "ES384"
+	case elliptic.P521():
+		return "ES512"
+	default:
+		return ""
+	}
+}
+
+// digest returns the hash of the signing input required by the given ECDSA
+// JWS algorithm.
+func digest(alg string, input []byte) []byte {
+	hash := crypto.SHA256
+
+	switch alg {
+	case "ES384":
+		hash = crypto.SHA384
+	case "ES512":
+		hash = crypto.SHA512
+	}
+
+	h := hash.New()
+	h.Write(input)
+
+	return h.Sum(nil)
+}
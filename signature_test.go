@@ -0,0 +1,215 @@
+package hypercat
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testKeys(t *testing.T) []crypto.Signer {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	keys := []crypto.Signer{edKey}
+
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		ecKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		keys = append(keys, ecKey)
+	}
+
+	return keys
+}
+
+func TestSignCatalogue(t *testing.T) {
+	for _, key := range testKeys(t) {
+		cat := testCatalogue(2)
+		cat.AddRel("relation", "value")
+
+		err := cat.Verify(key.Public())
+		if err != ErrMissingSignature {
+			t.Errorf("Verify error, expected '%v', got '%v'", ErrMissingSignature, err)
+		}
+
+		err = cat.Sign(key)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cat.Vals(SignatureRel)) != 1 {
+			t.Errorf("Sign should add a single signature, got '%v'", cat.Vals(SignatureRel))
+		}
+
+		// re-signing replaces the signature
+		err = cat.Sign(key)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cat.Vals(SignatureRel)) != 1 {
+			t.Errorf("Sign should replace the signature, got '%v'", cat.Vals(SignatureRel))
+		}
+
+		err = cat.Verify(key.Public())
+		if err != nil {
+			t.Errorf("Verify error: %v", err)
+		}
+
+		// reordering the catalogue doesn't affect the signature
+		cat.Items[0], cat.Items[1] = cat.Items[1], cat.Items[0]
+
+		err = cat.Verify(key.Public())
+		if err != nil {
+			t.Errorf("Verify error: %v", err)
+		}
+
+		cat.Items[0].Description = "Tampered"
+
+		err = cat.Verify(key.Public())
+		if err != ErrInvalidSignature {
+			t.Errorf("Verify error, expected '%v', got '%v'", ErrInvalidSignature, err)
+		}
+	}
+}
+
+func TestVerifyWithWrongKey(t *testing.T) {
+	keys := testKeys(t)
+
+	cat := testCatalogue(1)
+
+	err := cat.Sign(keys[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, key := range keys {
+		err = cat.Verify(key.Public())
+
+		if i == 0 && err != nil {
+			t.Errorf("Verify error: %v", err)
+		}
+
+		if i != 0 && err != ErrInvalidSignature {
+			t.Errorf("Verify error, expected '%v', got '%v'", ErrInvalidSignature, err)
+		}
+	}
+}
+
+func TestSignUnsupportedKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cat := testCatalogue(1)
+
+	err = cat.Sign(key)
+	if err != ErrUnsupportedKey {
+		t.Errorf("Sign error, expected '%v', got '%v'", ErrUnsupportedKey, err)
+	}
+
+	cat.AddRel(SignatureRel, "e30..c2ln")
+
+	err = cat.Verify(key.Public())
+	if err != ErrUnsupportedKey {
+		t.Errorf("Verify error, expected '%v', got '%v'", ErrUnsupportedKey, err)
+	}
+}
+
+func TestVerifyMalformedSignature(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	for _, signature := range []string{"", "a.b.c", "e30..!!", "!!..c2ln", "e30.c2ln"} {
+		item := NewItem("/foo", "description")
+		item.AddRel(SignatureRel, signature)
+
+		err := item.Verify(key.Public())
+		if err != ErrInvalidSignature {
+			t.Errorf("Verify error for '%v', expected '%v', got '%v'", signature, ErrInvalidSignature, err)
+		}
+	}
+}
+
+func TestParseVerifiesSignatures(t *testing.T) {
+	keys := testKeys(t)
+
+	cat := testCatalogue(2)
+
+	for i := range cat.Items {
+		err := cat.Items[i].Sign(keys[1])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	err := cat.Sign(keys[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	b, err := json.Marshal(cat)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = Parse(bytes.NewReader(b), VerifySignature(keys[0].Public()), VerifyItemSignatures(keys[1].Public()))
+	if err != nil {
+		t.Errorf("Parse error: %v", err)
+	}
+
+	_, err = Parse(bytes.NewReader(b), VerifySignature(keys[1].Public()))
+	if err != ErrInvalidSignature {
+		t.Errorf("Parse error, expected '%v', got '%v'", ErrInvalidSignature, err)
+	}
+
+	_, err = Parse(bytes.NewReader(b), VerifyItemSignatures(keys[0].Public()))
+	if err != ErrInvalidSignature {
+		t.Errorf("Parse error, expected '%v', got '%v'", ErrInvalidSignature, err)
+	}
+
+	b, _ = json.Marshal(testCatalogue(1))
+
+	_, err = Parse(bytes.NewReader(b), VerifySignature(keys[0].Public()))
+	if err != ErrMissingSignature {
+		t.Errorf("Parse error, expected '%v', got '%v'", ErrMissingSignature, err)
+	}
+}
+
+func TestSignedItemSurvivesClock(t *testing.T) {
+	key := testKeys(t)[0]
+
+	cat := NewHypercat("Catalogue description")
+	cat.Clock = testClock(time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	item := NewItem("/foo", "Signed item")
+	item.SetLastUpdated(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	err := item.Sign(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cat.AddItem(item)
+	cat.ReplaceItem(item)
+
+	err = cat.Items[0].Verify(key.Public())
+	if err != nil {
+		t.Errorf("Signed item verification error after adding to a catalogue with a Clock: %v", err)
+	}
+
+	if _, err := cat.LastUpdated(); err != nil {
+		t.Errorf("The catalogue should still be touched: %v", err)
+	}
+}
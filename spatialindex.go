@@ -0,0 +1,463 @@
+package hypercat
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultCellSize is the size in degrees of the grid cells used by a
+// SpatialIndex created with a non positive cell size.
+const DefaultCellSize = 1.0
+
+// earthRadius is the mean radius of the Earth in metres.
+const earthRadius = 6371008.8
+
+// Point is a WGS84 location, in degrees.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// SpatialIndex is a grid index over the locations of a catalogue's items,
+// given by their LatitudeRel and LongitudeRel values, supporting bounding
+// box, nearest neighbour and polygon queries without scanning every item.
+// Items without a valid location are not indexed. It is attached to a
+// catalogue with AddIndex, and is safe for concurrent use.
+//
+// Once attached, the index also answers geobound searches made with
+// Hypercat.Search.
+type SpatialIndex struct {
+	mu       sync.RWMutex
+	cellSize float64
+	cells    map[gridCell]map[string]*spatialDoc
+	docs     map[string]*spatialDoc
+	seq      uint64
+}
+
+// gridCell identifies a cell of the grid used by a SpatialIndex.
+type gridCell struct {
+	lat int
+	lng int
+}
+
+// spatialDoc is an item indexed by a SpatialIndex. Seq records the order in
+// which items were added, so results can be returned in catalogue order.
+type spatialDoc struct {
+	item  *Item
+	point Point
+	cell  gridCell
+	seq   uint64
+}
+
+// NewSpatialIndex is a constructor function that creates and returns an empty
+// SpatialIndex instance. Accepts the size in degrees of the cells of the grid
+// as a parameter, falling back to DefaultCellSize if this is not positive.
+// Smaller cells suit catalogues with densely clustered items.
+func NewSpatialIndex(cellSize float64) *SpatialIndex {
+	if cellSize <= 0 {
+		cellSize = DefaultCellSize
+	}
+
+	return &SpatialIndex{
+		cellSize: cellSize,
+		cells:    make(map[gridCell]map[string]*spatialDoc),
+		docs:     make(map[string]*spatialDoc),
+	}
+}
+
+// Reset implements Index.
+func (x *SpatialIndex) Reset(items Items) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.cells = make(map[gridCell]map[string]*spatialDoc)
+	x.docs = make(map[string]*spatialDoc)
+	x.seq = 0
+
+	for i := range items {
+		x.add(&items[i], 0)
+	}
+}
+
+// Apply implements Index.
+func (x *SpatialIndex) Apply(ev Event) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	switch ev.Type {
+	case ItemAdded:
+		x.remove(ev.Href)
+		x.add(ev.Item, 0)
+	case ItemReplaced:
+		var seq uint64
+
+		if doc, ok := x.docs[ev.Href]; ok {
+			seq = doc.seq
+		}
+
+		x.remove(ev.Href)
+		x.add(ev.Item, seq)
+	case ItemRemoved:
+		x.remove(ev.Href)
+	}
+}
+
+// Len returns the number of items within the index.
+func (x *SpatialIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.docs)
+}
+
+// Within returns a catalogue with the metadata of cat, normally the catalogue
+// the index is attached to, containing copies of the indexed items located
+// within the given bounding box, inclusive of its edges. Items are returned
+// in catalogue order, matching a geobound search made with Hypercat.Search.
+func (x *SpatialIndex) Within(cat *Hypercat, minLong, minLat, maxLong, maxLat float64) *Hypercat {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return cat.withItems(x.within(minLong, minLat, maxLong, maxLat, func(p Point) bool {
+		return p.Lat >= minLat && p.Lat <= maxLat && p.Lng >= minLong && p.Lng <= maxLong
+	}))
+}
+
+// WithinPolygon returns a catalogue with the metadata of cat containing
+// copies of the indexed items located within the given polygon, in catalogue
+// order. The polygon is treated as planar in latitude and longitude, its last
+// vertex is joined to its first, and items lying exactly on its edges may or
+// may not be included. A polygon with fewer than three vertices matches
+// nothing.
+func (x *SpatialIndex) WithinPolygon(cat *Hypercat, polygon []Point) *Hypercat {
+	if len(polygon) < 3 {
+		return cat.withItems(Items{})
+	}
+
+	minLong, minLat := polygon[0].Lng, polygon[0].Lat
+	maxLong, maxLat := minLong, minLat
+
+	for _, p := range polygon[1:] {
+		minLong, maxLong = math.Min(minLong, p.Lng), math.Max(maxLong, p.Lng)
+		minLat, maxLat = math.Min(minLat, p.Lat), math.Max(maxLat, p.Lat)
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return cat.withItems(x.within(minLong, minLat, maxLong, maxLat, func(p Point) bool {
+		return inPolygon(p, polygon)
+	}))
+}
+
+// Nearest returns a catalogue with the metadata of cat containing copies of
+// the n indexed items nearest to the given location and no further than
+// radius metres from it, ordered by increasing great-circle distance. A non
+// positive n returns every item within the radius, and a non positive radius
+// places no limit on distance.
+func (x *SpatialIndex) Nearest(cat *Hypercat, from Point, n int, radius float64) *Hypercat {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if radius <= 0 {
+		radius = math.Inf(1)
+	}
+
+	type candidate struct {
+		doc      *spatialDoc
+		distance float64
+	}
+
+	found := []candidate{}
+	center := x.cellOf(from)
+
+	consider := func(docs map[string]*spatialDoc) {
+		for _, doc := range docs {
+			d := distance(from, doc.point)
+			if d <= radius {
+				found = append(found, candidate{doc, d})
+			}
+		}
+	}
+
+	// Search rings of cells of increasing size around the location, until
+	// every item outside the searched area is known to be further away than
+	// the n nearest found so far, or than the radius, or the whole globe has
+	// been searched. Once the searched area holds more cells than are
+	// populated, the populated cells are scanned instead, as sparse indexes
+	// with small cells would otherwise visit a vast number of empty cells.
+	for r := 0; ; r++ {
+		scan := (2*r+1)*(2*r+1) > len(x.cells)
+
+		if scan {
+			found = found[:0]
+
+			for _, docs := range x.cells {
+				consider(docs)
+			}
+		} else {
+			x.ring(center, r, consider)
+		}
+
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].distance != found[j].distance {
+				return found[i].distance < found[j].distance
+			}
+
+			return found[i].doc.seq < found[j].doc.seq
+		})
+
+		if n > 0 && len(found) > n {
+			found = found[:n]
+		}
+
+		bound := x.outsideDistance(from, center, r)
+
+		if scan || math.IsInf(bound, 1) || bound > radius || (n > 0 && len(found) == n && found[n-1].distance <= bound) {
+			break
+		}
+	}
+
+	items := make(Items, len(found))
+
+	for i, c := range found {
+		items[i] = *c.doc.item.clone()
+	}
+
+	return cat.withItems(items)
+}
+
+// within returns copies of the indexed items in cells overlapping the given
+// bounding box for which match returns true, in catalogue order. It must be
+// called with the read lock held.
+func (x *SpatialIndex) within(minLong, minLat, maxLong, maxLat float64, match func(p Point) bool) Items {
+	docs := []*spatialDoc{}
+
+	if minLong <= maxLong && minLat <= maxLat {
+		// Indexed items lie within the globe, so the box can be clamped to it.
+		lo := x.cellOf(Point{Lat: clamp(minLat, 90), Lng: clamp(minLong, 180)})
+		hi := x.cellOf(Point{Lat: clamp(maxLat, 90), Lng: clamp(maxLong, 180)})
+
+		// Visit whichever is smaller of the cells overlapping the box and
+		// the populated cells.
+		if (hi.lat-lo.lat+1)*(hi.lng-lo.lng+1) > len(x.cells) {
+			for cell, cellDocs := range x.cells {
+				if cell.lat >= lo.lat && cell.lat <= hi.lat && cell.lng >= lo.lng && cell.lng <= hi.lng {
+					docs = appendMatching(docs, cellDocs, match)
+				}
+			}
+		} else {
+			for lat := lo.lat; lat <= hi.lat; lat++ {
+				for lng := lo.lng; lng <= hi.lng; lng++ {
+					docs = appendMatching(docs, x.cells[gridCell{lat, lng}], match)
+				}
+			}
+		}
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].seq < docs[j].seq
+	})
+
+	items := make(Items, len(docs))
+
+	for i, doc := range docs {
+		items[i] = *doc.item.clone()
+	}
+
+	return items
+}
+
+// appendMatching appends the docs located at points for which match returns
+// true.
+func appendMatching(docs []*spatialDoc, cellDocs map[string]*spatialDoc, match func(p Point) bool) []*spatialDoc {
+	for _, doc := range cellDocs {
+		if match(doc.point) {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs
+}
+
+// ring calls fn with the docs of every populated cell whose distance from the
+// center, in cells, is exactly r. It must be called with the read lock held.
+func (x *SpatialIndex) ring(center gridCell, r int, fn func(docs map[string]*spatialDoc)) {
+	visit := func(lat, lng int) {
+		if docs, ok := x.cells[gridCell{lat, lng}]; ok {
+			fn(docs)
+		}
+	}
+
+	if r == 0 {
+		visit(center.lat, center.lng)
+		return
+	}
+
+	for lng := center.lng - r; lng <= center.lng+r; lng++ {
+		visit(center.lat-r, lng)
+		visit(center.lat+r, lng)
+	}
+
+	for lat := center.lat - r + 1; lat < center.lat+r; lat++ {
+		visit(lat, center.lng-r)
+		visit(lat, center.lng+r)
+	}
+}
+
+// outsideDistance returns a lower bound on the distance in metres from the
+// given location to any point outside the cells within r cells of center.
+func (x *SpatialIndex) outsideDistance(from Point, center gridCell, r int) float64 {
+	south := float64(center.lat-r) * x.cellSize
+	north := float64(center.lat+r+1) * x.cellSize
+	west := math.Max(float64(center.lng-r)*x.cellSize, -180)
+	east := math.Min(float64(center.lng+r+1)*x.cellSize, 180)
+
+	bound := math.Inf(1)
+
+	if south > -90 {
+		bound = math.Min(bound, radians(from.Lat-south)*earthRadius)
+	}
+
+	if north < 90 {
+		bound = math.Min(bound, radians(north-from.Lat)*earthRadius)
+	}
+
+	// Points beyond the searched longitudes lie on the far side of its west
+	// or east meridian, which wrap around to meet at the antimeridian.
+	if west > -180 || east < 180 {
+		bound = math.Min(bound, meridianDistance(from.Lat, math.Min(from.Lng-west, east-from.Lng)))
+	}
+
+	return bound
+}
+
+// cellOf returns the cell containing the given point.
+func (x *SpatialIndex) cellOf(p Point) gridCell {
+	return gridCell{
+		lat: int(math.Floor(p.Lat / x.cellSize)),
+		lng: int(math.Floor(p.Lng / x.cellSize)),
+	}
+}
+
+// add indexes the given item if it has a valid location, using seq to order
+// it or the next sequence number if seq is 0. It must be called with the
+// write lock held.
+func (x *SpatialIndex) add(item *Item, seq uint64) {
+	p, ok := itemLocation(item)
+	if !ok {
+		return
+	}
+
+	if seq == 0 {
+		x.seq++
+		seq = x.seq
+	}
+
+	doc := &spatialDoc{
+		item:  item.clone(),
+		point: p,
+		cell:  x.cellOf(p),
+		seq:   seq,
+	}
+
+	if x.cells[doc.cell] == nil {
+		x.cells[doc.cell] = make(map[string]*spatialDoc)
+	}
+
+	x.cells[doc.cell][item.Href] = doc
+	x.docs[item.Href] = doc
+}
+
+// remove removes the item with the given href from the index, if present. It
+// must be called with the write lock held.
+func (x *SpatialIndex) remove(href string) {
+	doc, ok := x.docs[href]
+	if !ok {
+		return
+	}
+
+	delete(x.cells[doc.cell], href)
+
+	if len(x.cells[doc.cell]) == 0 {
+		delete(x.cells, doc.cell)
+	}
+
+	delete(x.docs, href)
+}
+
+// spatialIndex returns the first SpatialIndex attached to the catalogue, or nil
+// if there is none.
+func (h *Hypercat) spatialIndex() *SpatialIndex {
+	for _, idx := range h.Indexes {
+		if spatial, ok := idx.(*SpatialIndex); ok {
+			return spatial
+		}
+	}
+
+	return nil
+}
+
+// itemLocation returns the location of the item, and whether it has a valid
+// one.
+func itemLocation(item *Item) (Point, bool) {
+	lat, err := item.Float(LatitudeRel)
+	if err != nil || lat < -90 || lat > 90 {
+		return Point{}, false
+	}
+
+	lng, err := item.Float(LongitudeRel)
+	if err != nil || lng < -180 || lng > 180 {
+		return Point{}, false
+	}
+
+	return Point{Lat: lat, Lng: lng}, true
+}
+
+// inPolygon reports whether the point lies within the polygon, using the
+// even-odd rule.
+func inPolygon(p Point, polygon []Point) bool {
+	inside := false
+
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) && p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// distance returns the great-circle distance in metres between two points,
+// using the haversine formula.
+func distance(a, b Point) float64 {
+	dlat := radians(b.Lat - a.Lat)
+	dlng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Sin(dlng/2)*math.Sin(dlng/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// meridianDistance returns the great-circle distance in metres from a point
+// at the given latitude to the meridian dlng degrees of longitude away.
+func meridianDistance(lat, dlng float64) float64 {
+	if dlng >= 90 {
+		dlng = 90
+	}
+
+	return earthRadius * math.Asin(math.Cos(radians(lat))*math.Sin(radians(dlng)))
+}
+
+// clamp limits the value to the range [-limit, limit].
+func clamp(v, limit float64) float64 {
+	return math.Max(-limit, math.Min(v, limit))
+}
+
+// radians converts degrees to radians.
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
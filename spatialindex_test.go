@@ -0,0 +1,279 @@
+package hypercat
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// locatedCatalogue returns a catalogue of n items at pseudo-random locations
+// across the globe.
+func locatedCatalogue(n int) *Hypercat {
+	rnd := rand.New(rand.NewSource(1))
+	items := make(Items, n)
+
+	for i := range items {
+		item := NewItem(fmt.Sprintf("/%d", i), fmt.Sprintf("Item %d", i))
+		item.SetFloat(LatitudeRel, rnd.Float64()*180-90)
+		item.SetFloat(LongitudeRel, rnd.Float64()*360-180)
+		items[i] = *item
+	}
+
+	cat := NewHypercat("Catalogue description")
+	cat.AddItems(items)
+
+	return cat
+}
+
+// nearestScan returns the hrefs of the n items of the catalogue nearest to the
+// given location and within radius metres of it, by scanning every item.
+func nearestScan(cat *Hypercat, from Point, n int, radius float64) []string {
+	type candidate struct {
+		href     string
+		distance float64
+	}
+
+	found := []candidate{}
+
+	for i := range cat.Items {
+		p, ok := itemLocation(&cat.Items[i])
+		if !ok {
+			continue
+		}
+
+		if d := distance(from, p); radius <= 0 || d <= radius {
+			found = append(found, candidate{cat.Items[i].Href, d})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].distance < found[j].distance
+	})
+
+	result := []string{}
+
+	for i, c := range found {
+		if n > 0 && i == n {
+			break
+		}
+
+		result = append(result, c.href)
+	}
+
+	return result
+}
+
+func TestSpatialIndexWithin(t *testing.T) {
+	cat := locatedCatalogue(2000)
+	idx := NewSpatialIndex(5)
+	cat.AddIndex(idx)
+
+	var testcases = [][4]float64{
+		{-10, 40, 10, 60},
+		{-180, -90, 180, 90},
+		{-1e300, -1e300, 1e300, 1e300},
+		{170.5, -3.25, 179.75, 2},
+		{10, 10, -10, -10},
+	}
+
+	for _, testcase := range testcases {
+		query := url.Values{}
+
+		for i, param := range searchParams[GeoBoundSearchVal] {
+			query.Set(param, formatFloat(testcase[i]))
+		}
+
+		expected, err := cat.withItems(cat.Items).Search(query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got := idx.Within(cat, testcase[0], testcase[1], testcase[2], testcase[3])
+
+		if !reflect.DeepEqual(hrefs(got.Items), hrefs(expected.Items)) {
+			t.Errorf("SpatialIndex within error for '%v', expected '%v', got '%v'", testcase, hrefs(expected.Items), hrefs(got.Items))
+		}
+
+		// The attached index answers geobound searches of the catalogue.
+		searched, _ := cat.Search(query)
+
+		if !reflect.DeepEqual(hrefs(searched.Items), hrefs(expected.Items)) {
+			t.Errorf("Indexed search error for '%v', expected '%v', got '%v'", testcase, hrefs(expected.Items), hrefs(searched.Items))
+		}
+	}
+}
+
+func TestSpatialIndexNearest(t *testing.T) {
+	cat := locatedCatalogue(2000)
+	idx := NewSpatialIndex(5)
+	cat.AddIndex(idx)
+
+	var testcases = []struct {
+		from   Point
+		n      int
+		radius float64
+	}{
+		{Point{51.5, -0.125}, 5, 0},
+		{Point{51.5, -0.125}, 0, 1000000},
+		{Point{51.5, -0.125}, 3, 100},
+		{Point{0, 179.9}, 10, 0},
+		{Point{89.9, 0}, 10, 0},
+		{Point{-45, -90}, 0, 0},
+	}
+
+	for _, testcase := range testcases {
+		expected := nearestScan(cat, testcase.from, testcase.n, testcase.radius)
+		got := hrefs(idx.Nearest(cat, testcase.from, testcase.n, testcase.radius).Items)
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("SpatialIndex nearest error for '%v', expected '%v', got '%v'", testcase, expected, got)
+		}
+	}
+}
+
+func TestSpatialIndexNearestSparse(t *testing.T) {
+	cat := locatedCatalogue(3)
+	idx := NewSpatialIndex(0.001)
+	cat.AddIndex(idx)
+
+	from := Point{51.5, -0.125}
+	expected := nearestScan(cat, from, 5, 0)
+
+	done := make(chan []string)
+	go func() { done <- hrefs(idx.Nearest(cat, from, 5, 0).Items) }()
+
+	select {
+	case got := <-done:
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("SpatialIndex nearest error, expected '%v', got '%v'", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SpatialIndex nearest should not visit every cell of a sparse index")
+	}
+}
+
+func TestSpatialIndexWithinPolygon(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+
+	for i, p := range []Point{{1, 1}, {2, 1}, {1, 2}, {-1, 1}, {2.5, 2.5}} {
+		item := NewItem(fmt.Sprintf("/%d", i), "Item")
+		item.SetFloat(LatitudeRel, p.Lat)
+		item.SetFloat(LongitudeRel, p.Lng)
+		cat.AddItem(item)
+	}
+
+	idx := NewSpatialIndex(0)
+	cat.AddIndex(idx)
+
+	triangle := []Point{{0, 0}, {4, 0}, {0, 4}}
+
+	if got := hrefs(idx.WithinPolygon(cat, triangle).Items); !reflect.DeepEqual(got, []string{"/0", "/1", "/2"}) {
+		t.Errorf("SpatialIndex polygon error, got '%v'", got)
+	}
+
+	if got := idx.WithinPolygon(cat, triangle[:2]).Items; len(got) != 0 {
+		t.Errorf("SpatialIndex polygon error, expected no items, got '%v'", hrefs(got))
+	}
+}
+
+func TestSpatialIndexMaintenance(t *testing.T) {
+	cat := NewHypercat("Catalogue description")
+	idx := NewSpatialIndex(0)
+	cat.AddIndex(idx)
+
+	for i := 0; i < 3; i++ {
+		item := NewItem(fmt.Sprintf("/%d", i), "Item")
+		item.SetFloat(LatitudeRel, float64(i))
+		item.SetFloat(LongitudeRel, float64(i))
+		cat.AddItem(item)
+	}
+
+	cat.AddItem(NewItem("/nowhere", "Item without a location"))
+
+	moved := NewItem("/0", "Moved item")
+	moved.SetFloat(LatitudeRel, 2.5)
+	moved.SetFloat(LongitudeRel, 2.5)
+	cat.ReplaceItem(moved)
+
+	cat.RemoveItem("/1")
+
+	if idx.Len() != 2 {
+		t.Errorf("SpatialIndex length error, expected '%v', got '%v'", 2, idx.Len())
+	}
+
+	if got := hrefs(idx.Within(cat, 2, 2, 3, 3).Items); !reflect.DeepEqual(got, []string{"/0", "/2"}) {
+		t.Errorf("SpatialIndex within error, expected catalogue order, got '%v'", got)
+	}
+
+	if got := hrefs(idx.Within(cat, -1, -1, 1.5, 1.5).Items); len(got) != 0 {
+		t.Errorf("SpatialIndex within error, expected no items, got '%v'", got)
+	}
+
+	if got := hrefs(idx.Nearest(cat, Point{0, 0}, 1, 0).Items); !reflect.DeepEqual(got, []string{"/2"}) {
+		t.Errorf("SpatialIndex nearest error, got '%v'", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	london := Point{51.5074, -0.1278}
+	paris := Point{48.8566, 2.3522}
+
+	if d := distance(london, paris); math.Abs(d-343500) > 1000 {
+		t.Errorf("Distance error, expected about '%v', got '%v'", 343500, d)
+	}
+}
+
+func benchmarkBounds() url.Values {
+	return url.Values{
+		"geobound-minlong": {"-10"},
+		"geobound-minlat":  {"40"},
+		"geobound-maxlong": {"10"},
+		"geobound-maxlat":  {"60"},
+	}
+}
+
+func BenchmarkGeoBoundScan(b *testing.B) {
+	cat := locatedCatalogue(100000)
+	query := benchmarkBounds()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cat.Search(query)
+	}
+}
+
+func BenchmarkGeoBoundIndex(b *testing.B) {
+	cat := locatedCatalogue(100000)
+	cat.AddIndex(NewSpatialIndex(0))
+	query := benchmarkBounds()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cat.Search(query)
+	}
+}
+
+func BenchmarkNearestScan(b *testing.B) {
+	cat := locatedCatalogue(100000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		nearestScan(cat, Point{51.5, -0.125}, 10, 0)
+	}
+}
+
+func BenchmarkNearestIndex(b *testing.B) {
+	cat := locatedCatalogue(100000)
+	idx := NewSpatialIndex(0)
+	cat.AddIndex(idx)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		idx.Nearest(cat, Point{51.5, -0.125}, 10, 0)
+	}
+}
@@ -0,0 +1,105 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Stats is a profile of the items of a catalogue, as produced by
+// Hypercat.Stats, intended for monitoring the quality of catalogue data. Its
+// JSON keys are hyphenated to match the style of the catalogue's own fields,
+// such as item-metadata.
+type Stats struct {
+	Items          int            `json:"items"`
+	SubCatalogues  int            `json:"sub-catalogues"`   // Items for which IsCatalogue returns true.
+	LocatedItems   int            `json:"located-items"`    // Items with a valid latitude and longitude.
+	RelCounts      map[string]int `json:"rel-counts"`       // Occurrences of each rel within item metadata.
+	DistinctValues map[string]int `json:"distinct-values"`  // Number of distinct values of each rel within item metadata.
+	DuplicateRels  map[string]int `json:"duplicate-rels"`   // Number of items in which each rel occurs more than once.
+	Extent         *Extent        `json:"extent,omitempty"` // Bounding box of the located items, or nil if there are none.
+	Size           int            `json:"size"`             // Size in bytes of the catalogue's JSON encoding.
+}
+
+// Extent is a geographic bounding box, in WGS84 degrees.
+type Extent struct {
+	MinLat float64 `json:"min-lat"`
+	MinLng float64 `json:"min-long"`
+	MaxLat float64 `json:"max-lat"`
+	MaxLng float64 `json:"max-long"`
+}
+
+// Stats analyses the items of the catalogue, returning counts of items, sub
+// catalogues and located items, the frequency and distinct values of each
+// rel, rels repeated within items, the geographic extent of the items and the
+// size of the serialized catalogue. Descriptions are counted as
+// hasDescription rels. Returns an error if the catalogue can't be serialized.
+func (h *Hypercat) Stats() (*Stats, error) {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		Items:          len(h.Items),
+		RelCounts:      map[string]int{},
+		DistinctValues: map[string]int{},
+		DuplicateRels:  map[string]int{},
+		Size:           len(b),
+	}
+
+	values := map[string]map[string]bool{}
+
+	for i := range h.Items {
+		item := &h.Items[i]
+
+		if item.IsCatalogue() {
+			stats.SubCatalogues++
+		}
+
+		if p, ok := itemLocation(item); ok {
+			stats.LocatedItems++
+			stats.Extent = stats.Extent.extend(p)
+		}
+
+		counts := map[string]int{}
+
+		for _, rel := range item.allMetadata() {
+			counts[rel.Rel]++
+
+			if values[rel.Rel] == nil {
+				values[rel.Rel] = map[string]bool{}
+			}
+
+			values[rel.Rel][rel.Val] = true
+		}
+
+		for rel, count := range counts {
+			stats.RelCounts[rel] += count
+
+			if count > 1 {
+				stats.DuplicateRels[rel]++
+			}
+		}
+	}
+
+	for rel, vals := range values {
+		stats.DistinctValues[rel] = len(vals)
+	}
+
+	return stats, nil
+}
+
+// extend returns the extent enlarged to contain the given point, or an extent
+// containing only the point if e is nil.
+func (e *Extent) extend(p Point) *Extent {
+	if e == nil {
+		return &Extent{MinLat: p.Lat, MinLng: p.Lng, MaxLat: p.Lat, MaxLng: p.Lng}
+	}
+
+	return &Extent{
+		MinLat: math.Min(e.MinLat, p.Lat),
+		MinLng: math.Min(e.MinLng, p.Lng),
+		MaxLat: math.Max(e.MaxLat, p.Lat),
+		MaxLng: math.Max(e.MaxLng, p.Lng),
+	}
+}
@@ -0,0 +1,68 @@
+package hypercat
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	cat := searchCatalogue()
+
+	tagged := NewItem("/tagged", "Tagged")
+	tagged.AddRel("urn:example:tag", "red")
+	tagged.AddRel("urn:example:tag", "green")
+	tagged.AddRel("urn:example:name", "apple")
+	cat.AddItem(tagged)
+
+	stats, err := cat.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	b, _ := json.Marshal(cat)
+
+	expected := &Stats{
+		Items:         4,
+		SubCatalogues: 1,
+		LocatedItems:  2,
+		RelCounts: map[string]int{
+			DescriptionRel:     4,
+			LatitudeRel:        2,
+			LongitudeRel:       2,
+			ContentTypeRel:     1,
+			"urn:example:name": 4,
+			"urn:example:tag":  2,
+		},
+		DistinctValues: map[string]int{
+			DescriptionRel:     4,
+			LatitudeRel:        2,
+			LongitudeRel:       2,
+			ContentTypeRel:     1,
+			"urn:example:name": 3,
+			"urn:example:tag":  2,
+		},
+		DuplicateRels: map[string]int{"urn:example:tag": 1},
+		Extent:        &Extent{MinLat: 48.85, MinLng: -0.125, MaxLat: 51.5, MaxLng: 2.35},
+		Size:          len(b),
+	}
+
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Stats error, expected '%+v', got '%+v'", expected, stats)
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	stats, err := NewHypercat("Empty").Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	b, _ := json.Marshal(stats)
+	expected := `{"items":0,"sub-catalogues":0,"located-items":0,"rel-counts":{},"distinct-values":{},"duplicate-rels":{},"size":` + strconv.Itoa(stats.Size) + `}`
+
+	if string(b) != expected {
+		t.Errorf("Stats JSON error, expected '%v', got '%v'", expected, string(b))
+	}
+}
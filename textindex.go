@@ -0,0 +1,172 @@
+package hypercat
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TextIndex is a full-text inverted index over the descriptions and metadata
+// values of a catalogue's items, supporting keyword searches beyond the exact
+// and prefix matching defined by the Hypercat specification. It is attached
+// to a catalogue with AddIndex, and is safe for concurrent use.
+//
+// Text is split into terms at every character that is neither a letter nor a
+// digit, and terms are case folded, so that "Air-Quality" is indexed as "air"
+// and "quality".
+type TextIndex struct {
+	mu       sync.RWMutex
+	postings map[string]map[string]int // Frequency of each term within each item, keyed by term and href.
+	docs     map[string]*textDoc
+}
+
+// textDoc is an item indexed by a TextIndex, with the frequency of each of its
+// terms.
+type textDoc struct {
+	item  *Item
+	terms map[string]int
+}
+
+// NewTextIndex is a constructor function that creates and returns an empty
+// TextIndex instance.
+func NewTextIndex() *TextIndex {
+	return &TextIndex{
+		postings: make(map[string]map[string]int),
+		docs:     make(map[string]*textDoc),
+	}
+}
+
+// Reset implements Index.
+func (x *TextIndex) Reset(items Items) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.postings = make(map[string]map[string]int)
+	x.docs = make(map[string]*textDoc, len(items))
+
+	for i := range items {
+		x.add(&items[i])
+	}
+}
+
+// Apply implements Index.
+func (x *TextIndex) Apply(ev Event) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	switch ev.Type {
+	case ItemAdded, ItemReplaced:
+		x.remove(ev.Href)
+		x.add(ev.Item)
+	case ItemRemoved:
+		x.remove(ev.Href)
+	}
+}
+
+// Len returns the number of items within the index.
+func (x *TextIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.docs)
+}
+
+// Search returns a catalogue with the metadata of cat, normally the catalogue
+// the index is attached to, containing copies of the indexed items matching
+// any term of the query. Items are ordered by relevance, being the total
+// frequency of the query's terms within their descriptions and metadata
+// values, with ties ordered by href. A query without terms matches nothing.
+func (x *TextIndex) Search(cat *Hypercat, query string) *Hypercat {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	scores := map[string]int{}
+
+	for term := range termFrequencies(query) {
+		for href, freq := range x.postings[term] {
+			scores[href] += freq
+		}
+	}
+
+	hrefs := make([]string, 0, len(scores))
+
+	for href := range scores {
+		hrefs = append(hrefs, href)
+	}
+
+	sort.Slice(hrefs, func(i, j int) bool {
+		if scores[hrefs[i]] != scores[hrefs[j]] {
+			return scores[hrefs[i]] > scores[hrefs[j]]
+		}
+
+		return hrefs[i] < hrefs[j]
+	})
+
+	items := make(Items, len(hrefs))
+
+	for i, href := range hrefs {
+		items[i] = *x.docs[href].item.clone()
+	}
+
+	return cat.withItems(items)
+}
+
+// add indexes the given item. It must be called with the write lock held.
+func (x *TextIndex) add(item *Item) {
+	doc := &textDoc{
+		item:  item.clone(),
+		terms: map[string]int{},
+	}
+
+	for _, rel := range item.allMetadata() {
+		for term, freq := range termFrequencies(rel.Val) {
+			doc.terms[term] += freq
+		}
+	}
+
+	for term, freq := range doc.terms {
+		if x.postings[term] == nil {
+			x.postings[term] = make(map[string]int)
+		}
+
+		x.postings[term][item.Href] = freq
+	}
+
+	x.docs[item.Href] = doc
+}
+
+// remove removes the item with the given href from the index, if present. It
+// must be called with the write lock held.
+func (x *TextIndex) remove(href string) {
+	doc, ok := x.docs[href]
+	if !ok {
+		return
+	}
+
+	for term := range doc.terms {
+		delete(x.postings[term], href)
+
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+
+	delete(x.docs, href)
+}
+
+// termFrequencies splits the text into case folded terms, returning the number
+// of times each occurs.
+func termFrequencies(text string) map[string]int {
+	terms := map[string]int{}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, field := range fields {
+		terms[strings.ToLower(field)]++
+	}
+
+	return terms
+}
@@ -0,0 +1,83 @@
+package hypercat
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTextIndexSearch(t *testing.T) {
+	cat := searchCatalogue()
+	idx := NewTextIndex()
+	cat.AddIndex(idx)
+
+	var testcases = []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{}},
+		{"tomato", []string{}},
+		{"APPLE", []string{"/sensors/london"}},
+		{"sensor", []string{"/sensors/london", "/sensors/paris"}},
+		{"paris sensor", []string{"/sensors/paris", "/sensors/london"}},
+		{"parks, cherry!", []string{"/parks"}},
+	}
+
+	for _, testcase := range testcases {
+		results := idx.Search(cat, testcase.query)
+
+		if got := hrefs(results.Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("TextIndex search error for '%v', expected '%v', got '%v'", testcase.query, testcase.expected, got)
+		}
+
+		if results.Description != cat.Description {
+			t.Errorf("TextIndex search description error, expected '%v', got '%v'", cat.Description, results.Description)
+		}
+	}
+}
+
+func TestTextIndexMaintenance(t *testing.T) {
+	cat := searchCatalogue()
+	idx := NewTextIndex()
+	cat.AddIndex(idx)
+
+	oslo := NewItem("/sensors/oslo", "Oslo sensor")
+	oslo.SetDescriptionIn("nb", "Oslo måler")
+	cat.AddItem(oslo)
+
+	paris := NewItem("/sensors/paris", "Paris station")
+	cat.ReplaceItem(paris)
+
+	cat.RemoveItem("/parks")
+
+	var testcases = []struct {
+		query    string
+		expected []string
+	}{
+		{"sensor", []string{"/sensors/london", "/sensors/oslo"}},
+		{"MÅLER", []string{"/sensors/oslo"}},
+		{"banana", []string{}},
+		{"station", []string{"/sensors/paris"}},
+		{"cherry", []string{}},
+	}
+
+	for _, testcase := range testcases {
+		if got := hrefs(idx.Search(cat, testcase.query).Items); !reflect.DeepEqual(got, testcase.expected) {
+			t.Errorf("TextIndex search error for '%v', expected '%v', got '%v'", testcase.query, testcase.expected, got)
+		}
+	}
+
+	if idx.Len() != 3 {
+		t.Errorf("TextIndex length error, expected '%v', got '%v'", 3, idx.Len())
+	}
+
+	base, _ := url.Parse("http://example.com/cat")
+	cat.BaseURL = base
+	cat.ResolveHrefs()
+
+	expected := []string{"http://example.com/sensors/paris"}
+
+	if got := hrefs(idx.Search(cat, "station").Items); !reflect.DeepEqual(got, expected) {
+		t.Errorf("TextIndex search error after resolving hrefs, expected '%v', got '%v'", expected, got)
+	}
+}
@@ -0,0 +1,291 @@
+package hypercat
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// value returns the value of the first Rel matching the given key, or a
+// RelError wrapping ErrRelNotFound if there is none.
+func (m Metadata) value(key string) (string, error) {
+	val, ok := m.First(key)
+	if !ok {
+		return "", &RelError{Rel: key, Err: ErrRelNotFound}
+	}
+
+	return val, nil
+}
+
+// relError returns a RelError describing a failure to convert the value of a
+// Rel, unwrapping any strconv.NumError to avoid repeating the value.
+func relError(key, val string, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = numErr.Err
+	}
+
+	return &RelError{Rel: key, Val: val, Err: err}
+}
+
+// Float returns the value of the first Rel matching the given key as a
+// float64. Returns a RelError if the Rel isn't defined or its value isn't a
+// valid number.
+//
+// All of the typed accessors consult the DefaultRegistry, returning a RelError
+// if the rel is registered with a different ValueType (wrapping
+// ErrTypeMismatch) or if its value isn't one of the registered allowed values.
+func (m Metadata) Float(key string) (float64, error) {
+	val, err := m.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkRegistered(key, val, FloatValue)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, relError(key, val, err)
+	}
+
+	return f, nil
+}
+
+// Int returns the value of the first Rel matching the given key as an int64.
+// Returns a RelError if the Rel isn't defined or its value isn't a valid
+// integer.
+func (m Metadata) Int(key string) (int64, error) {
+	val, err := m.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkRegistered(key, val, IntValue)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, relError(key, val, err)
+	}
+
+	return i, nil
+}
+
+// Bool returns the value of the first Rel matching the given key as a bool,
+// accepting the values accepted by strconv.ParseBool. Returns a RelError if
+// the Rel isn't defined or its value isn't a valid boolean.
+func (m Metadata) Bool(key string) (bool, error) {
+	val, err := m.value(key)
+	if err != nil {
+		return false, err
+	}
+
+	err = checkRegistered(key, val, BoolValue)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, relError(key, val, err)
+	}
+
+	return b, nil
+}
+
+// Time returns the value of the first Rel matching the given key parsed as an
+// ISO 8601 (RFC 3339) timestamp. Returns a RelError if the Rel isn't defined
+// or its value isn't a valid timestamp.
+func (m Metadata) Time(key string) (time.Time, error) {
+	val, err := m.value(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = checkRegistered(key, val, TimeValue)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return time.Time{}, relError(key, val, err)
+	}
+
+	return t, nil
+}
+
+// URL returns the value of the first Rel matching the given key parsed as a
+// URL. Returns a RelError if the Rel isn't defined or its value isn't a valid
+// URL.
+func (m Metadata) URL(key string) (*url.URL, error) {
+	val, err := m.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRegistered(key, val, URLValue)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(val)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+
+		return nil, relError(key, val, err)
+	}
+
+	return u, nil
+}
+
+// formatFloat returns the string representation of a float64 rel value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatInt returns the string representation of an int64 rel value.
+func formatInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+// formatTime returns the ISO 8601 representation of a time rel value.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Float returns the value of the given rel of the item as a float64. See
+// Metadata.Float for details.
+func (item *Item) Float(rel string) (float64, error) {
+	return item.Metadata.Float(rel)
+}
+
+// Int returns the value of the given rel of the item as an int64. See
+// Metadata.Int for details.
+func (item *Item) Int(rel string) (int64, error) {
+	return item.Metadata.Int(rel)
+}
+
+// Bool returns the value of the given rel of the item as a bool. See
+// Metadata.Bool for details.
+func (item *Item) Bool(rel string) (bool, error) {
+	return item.Metadata.Bool(rel)
+}
+
+// Time returns the value of the given rel of the item as a time.Time. See
+// Metadata.Time for details.
+func (item *Item) Time(rel string) (time.Time, error) {
+	return item.Metadata.Time(rel)
+}
+
+// URL returns the value of the given rel of the item as a URL. See
+// Metadata.URL for details.
+func (item *Item) URL(rel string) (*url.URL, error) {
+	return item.Metadata.URL(rel)
+}
+
+// SetFloat sets the value of the given rel of the item to a float64,
+// replacing any existing values.
+func (item *Item) SetFloat(rel string, f float64) {
+	item.Metadata.Set(rel, formatFloat(f))
+}
+
+// SetInt sets the value of the given rel of the item to an int64, replacing
+// any existing values.
+func (item *Item) SetInt(rel string, i int64) {
+	item.Metadata.Set(rel, formatInt(i))
+}
+
+// SetBool sets the value of the given rel of the item to a bool, replacing any
+// existing values.
+func (item *Item) SetBool(rel string, b bool) {
+	item.Metadata.Set(rel, strconv.FormatBool(b))
+}
+
+// SetTime sets the value of the given rel of the item to an ISO 8601 timestamp,
+// replacing any existing values.
+func (item *Item) SetTime(rel string, t time.Time) {
+	item.Metadata.Set(rel, formatTime(t))
+}
+
+// SetURL sets the value of the given rel of the item to a URL, replacing any
+// existing values. A nil URL removes the rel.
+func (item *Item) SetURL(rel string, u *url.URL) {
+	if u == nil {
+		item.Metadata.Remove(rel)
+		return
+	}
+
+	item.Metadata.Set(rel, u.String())
+}
+
+// Float returns the value of the given rel of the catalogue as a float64. See
+// Metadata.Float for details.
+func (h *Hypercat) Float(rel string) (float64, error) {
+	return h.Metadata.Float(rel)
+}
+
+// Int returns the value of the given rel of the catalogue as an int64. See
+// Metadata.Int for details.
+func (h *Hypercat) Int(rel string) (int64, error) {
+	return h.Metadata.Int(rel)
+}
+
+// Bool returns the value of the given rel of the catalogue as a bool. See
+// Metadata.Bool for details.
+func (h *Hypercat) Bool(rel string) (bool, error) {
+	return h.Metadata.Bool(rel)
+}
+
+// Time returns the value of the given rel of the catalogue as a time.Time. See
+// Metadata.Time for details.
+func (h *Hypercat) Time(rel string) (time.Time, error) {
+	return h.Metadata.Time(rel)
+}
+
+// URL returns the value of the given rel of the catalogue as a URL. See
+// Metadata.URL for details.
+func (h *Hypercat) URL(rel string) (*url.URL, error) {
+	return h.Metadata.URL(rel)
+}
+
+// SetFloat sets the value of the given rel of the catalogue to a float64,
+// replacing any existing values.
+func (h *Hypercat) SetFloat(rel string, f float64) {
+	h.SetRel(rel, formatFloat(f))
+}
+
+// SetInt sets the value of the given rel of the catalogue to an int64,
+// replacing any existing values.
+func (h *Hypercat) SetInt(rel string, i int64) {
+	h.SetRel(rel, formatInt(i))
+}
+
+// SetBool sets the value of the given rel of the catalogue to a bool,
+// replacing any existing values.
+func (h *Hypercat) SetBool(rel string, b bool) {
+	h.SetRel(rel, strconv.FormatBool(b))
+}
+
+// SetTime sets the value of the given rel of the catalogue to an ISO 8601
+// timestamp, replacing any existing values.
+func (h *Hypercat) SetTime(rel string, t time.Time) {
+	h.SetRel(rel, formatTime(t))
+}
+
+// SetURL sets the value of the given rel of the catalogue to a URL, replacing
+// any existing values. A nil URL removes the rel.
+func (h *Hypercat) SetURL(rel string, u *url.URL) {
+	if u == nil {
+		h.RemoveRel(rel)
+		return
+	}
+
+	h.SetRel(rel, u.String())
+}
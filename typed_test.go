@@ -0,0 +1,155 @@
+package hypercat
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestItemTypedAccessors(t *testing.T) {
+	item := NewItem("/foo", "description")
+	when := time.Date(2016, 3, 1, 12, 30, 0, 500, time.FixedZone("CET", 3600))
+	homepage, _ := url.Parse("http://www.hypercat.io/standard.html")
+
+	item.SetFloat(LatitudeRel, 51.5)
+	item.SetInt("count", -42)
+	item.SetBool("active", true)
+	item.SetTime("created", when)
+	item.SetURL(HomepageRel, homepage)
+	item.SetFloat(LatitudeRel, 51.25)
+
+	expected := Metadata{
+		Rel{Rel: LatitudeRel, Val: "51.25"},
+		Rel{Rel: "count", Val: "-42"},
+		Rel{Rel: "active", Val: "true"},
+		Rel{Rel: "created", Val: "2016-03-01T11:30:00.0000005Z"},
+		Rel{Rel: HomepageRel, Val: "http://www.hypercat.io/standard.html"},
+	}
+
+	if !reflect.DeepEqual(item.Metadata, expected) {
+		t.Errorf("Item setter error, expected '%v', got '%v'", expected, item.Metadata)
+	}
+
+	f, err := item.Float(LatitudeRel)
+	if err != nil || f != 51.25 {
+		t.Errorf("Item Float error, expected '%v', got '%v' (%v)", 51.25, f, err)
+	}
+
+	i, err := item.Int("count")
+	if err != nil || i != -42 {
+		t.Errorf("Item Int error, expected '%v', got '%v' (%v)", -42, i, err)
+	}
+
+	b, err := item.Bool("active")
+	if err != nil || !b {
+		t.Errorf("Item Bool error, expected '%v', got '%v' (%v)", true, b, err)
+	}
+
+	tm, err := item.Time("created")
+	if err != nil || !tm.Equal(when) {
+		t.Errorf("Item Time error, expected '%v', got '%v' (%v)", when, tm, err)
+	}
+
+	u, err := item.URL(HomepageRel)
+	if err != nil || u.String() != homepage.String() {
+		t.Errorf("Item URL error, expected '%v', got '%v' (%v)", homepage, u, err)
+	}
+}
+
+func TestTypedAccessorErrors(t *testing.T) {
+	item := NewItem("/foo", "description")
+	item.AddRel("value", "abc")
+	item.AddRel("url", "%zz")
+
+	var testcases = []struct {
+		fn       func() error
+		rel      string
+		val      string
+		expected error
+	}{
+		{func() error { _, err := item.Float("missing"); return err }, "missing", "", ErrRelNotFound},
+		{func() error { _, err := item.Float("value"); return err }, "value", "abc", strconv.ErrSyntax},
+		{func() error { _, err := item.Int("value"); return err }, "value", "abc", strconv.ErrSyntax},
+		{func() error { _, err := item.Bool("value"); return err }, "value", "abc", strconv.ErrSyntax},
+		{func() error { _, err := item.Time("value"); return err }, "value", "abc", nil},
+		{func() error { _, err := item.URL("url"); return err }, "url", "%zz", nil},
+	}
+
+	for _, testcase := range testcases {
+		err := testcase.fn()
+
+		relErr, ok := err.(*RelError)
+		if !ok {
+			t.Errorf("Typed accessor error, expected a RelError, got '%v'", err)
+			continue
+		}
+
+		if relErr.Rel != testcase.rel || relErr.Val != testcase.val {
+			t.Errorf("Typed accessor error, expected rel '%v' and val '%v', got '%v'", testcase.rel, testcase.val, relErr)
+		}
+
+		if testcase.expected != nil && !errors.Is(err, testcase.expected) {
+			t.Errorf("Typed accessor error, expected '%v', got '%v'", testcase.expected, err)
+		}
+	}
+
+	_, err := item.Int("value")
+	expected := `Invalid value "abc" for "value": invalid syntax`
+
+	if err.Error() != expected {
+		t.Errorf("Typed accessor error message, expected '%v', got '%v'", expected, err.Error())
+	}
+}
+
+func TestCatalogueTypedAccessors(t *testing.T) {
+	cat := NewHypercat("description")
+	cat.Feed = NewFeed(10)
+
+	cat.SetInt("count", 1)
+	cat.SetInt("count", 2)
+	cat.SetBool("public", false)
+
+	i, err := cat.Int("count")
+	if err != nil || i != 2 {
+		t.Errorf("Catalogue Int error, expected '%v', got '%v' (%v)", 2, i, err)
+	}
+
+	b, err := cat.Bool("public")
+	if err != nil || b {
+		t.Errorf("Catalogue Bool error, expected '%v', got '%v' (%v)", false, b, err)
+	}
+
+	events, _ := cat.Feed.Since(0)
+	types := []EventType{}
+
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+
+	expected := []EventType{RelAdded, RelReplaced, RelAdded}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Catalogue setter events error, expected '%v', got '%v'", expected, types)
+	}
+}
+
+func TestSetNilURL(t *testing.T) {
+	item := NewItem("/foo", "description")
+	item.AddRel(HomepageRel, "http://example.com")
+	item.SetURL(HomepageRel, nil)
+
+	if item.HasRel(HomepageRel) {
+		t.Errorf("Item SetURL with a nil URL should remove the rel, got '%v'", item.Vals(HomepageRel))
+	}
+
+	cat := NewHypercat("description")
+	cat.AddRel(HomepageRel, "http://example.com")
+	cat.SetURL(HomepageRel, nil)
+
+	if cat.HasRel(HomepageRel) {
+		t.Errorf("Catalogue SetURL with a nil URL should remove the rel, got '%v'", cat.Vals(HomepageRel))
+	}
+}
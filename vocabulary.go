@@ -0,0 +1,350 @@
+package hypercat
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValueType identifies the type of the values permitted for a rel.
+type ValueType int
+
+const (
+	// StringValue permits any string value.
+	StringValue ValueType = iota
+
+	// FloatValue permits values accepted by Metadata.Float.
+	FloatValue
+
+	// IntValue permits values accepted by Metadata.Int.
+	IntValue
+
+	// BoolValue permits values accepted by Metadata.Bool.
+	BoolValue
+
+	// TimeValue permits values accepted by Metadata.Time.
+	TimeValue
+
+	// URLValue permits values accepted by Metadata.URL.
+	URLValue
+)
+
+// String returns the name of the value type.
+func (t ValueType) String() string {
+	switch t {
+	case StringValue:
+		return "string"
+	case FloatValue:
+		return "float"
+	case IntValue:
+		return "int"
+	case BoolValue:
+		return "bool"
+	case TimeValue:
+		return "time"
+	case URLValue:
+		return "url"
+	default:
+		return "ValueType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Scope identifies the metadata to which a rel definition applies.
+type Scope int
+
+const (
+	// AnyScope applies a definition to both item and catalogue metadata.
+	AnyScope Scope = iota
+
+	// ItemScope applies a definition to item metadata only.
+	ItemScope
+
+	// CatalogueScope applies a definition to catalogue metadata only.
+	CatalogueScope
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case AnyScope:
+		return "any"
+	case ItemScope:
+		return "item"
+	case CatalogueScope:
+		return "catalogue"
+	default:
+		return "Scope(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// includes reports whether the scope includes the given scope.
+func (s Scope) includes(other Scope) bool {
+	return s == AnyScope || s == other
+}
+
+// RelDefinition describes a rel within a vocabulary, along with the
+// constraints on its values.
+type RelDefinition struct {
+	Rel     string
+	Label   string
+	Type    ValueType
+	Scope   Scope    // Metadata to which the definition applies, both item and catalogue by default.
+	Min     int      // Minimum number of occurrences within an item or catalogue in scope.
+	Max     int      // Maximum number of occurrences, or 0 for no limit.
+	Allowed []string // Permitted values, or empty to permit any value of Type.
+
+	// Prefix makes the definition apply to every rel starting with Rel, such
+	// as the descriptions in each language, which are counted together.
+	Prefix bool
+}
+
+// CheckValue returns an error if the given value is not of the definition's
+// type or is not one of its allowed values.
+func (def RelDefinition) CheckValue(val string) error {
+	var err error
+
+	switch def.Type {
+	case FloatValue:
+		_, err = strconv.ParseFloat(val, 64)
+	case IntValue:
+		_, err = strconv.ParseInt(val, 10, 64)
+	case BoolValue:
+		_, err = strconv.ParseBool(val)
+	case TimeValue:
+		_, err = time.Parse(time.RFC3339Nano, val)
+	case URLValue:
+		_, err = url.Parse(val)
+	}
+
+	if err != nil {
+		return relError(def.Rel, val, err)
+	}
+
+	if len(def.Allowed) == 0 {
+		return nil
+	}
+
+	for _, allowed := range def.Allowed {
+		if val == allowed {
+			return nil
+		}
+	}
+
+	return &RelError{Rel: def.Rel, Val: val, Err: ErrValueNotAllowed}
+}
+
+// Registry is a vocabulary of rel definitions, which is consulted when
+// validating catalogues. It is safe for concurrent use.
+type Registry struct {
+	mu   sync.RWMutex
+	defs map[string]RelDefinition
+}
+
+// NewRegistry is a constructor function that creates and returns an empty
+// Registry instance.
+func NewRegistry() *Registry {
+	return &Registry{
+		defs: make(map[string]RelDefinition),
+	}
+}
+
+// DefaultRegistry is the registry used by the package level Register and
+// Validate functions and consulted by the typed metadata accessors. It is
+// initialized with the standard Hypercat rels.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	searchVals := []string{SimpleSearchVal, GeoBoundSearchVal, LexicographicSearchVal, MultiSearchVal, PrefixSearchVal}
+
+	for _, def := range []RelDefinition{
+		{Rel: DescriptionRelPrefix, Label: "Description", Type: StringValue, Min: 1, Prefix: true},
+		{Rel: DescriptionRel, Label: "Description", Type: StringValue, Max: 1},
+		{Rel: ContentTypeRel, Label: "Content type", Type: StringValue, Max: 1},
+		{Rel: HomepageRel, Label: "Homepage", Type: URLValue},
+		{Rel: ContainsContentTypeRel, Label: "Contains content type", Type: StringValue},
+		{Rel: SupportsSearchRel, Label: "Supports search", Type: StringValue, Allowed: searchVals},
+		{Rel: LastUpdatedRel, Label: "Last updated", Type: TimeValue, Max: 1},
+		{Rel: NextPageRel, Label: "Next page", Type: URLValue, Max: 1},
+		{Rel: SignatureRel, Label: "Signature", Type: StringValue, Max: 1},
+		{Rel: LatitudeRel, Label: "Latitude", Type: FloatValue, Max: 1},
+		{Rel: LongitudeRel, Label: "Longitude", Type: FloatValue, Max: 1},
+	} {
+		DefaultRegistry.Register(def)
+	}
+}
+
+// Register adds a rel definition to the DefaultRegistry.
+func Register(def RelDefinition) error {
+	return DefaultRegistry.Register(def)
+}
+
+// Validate checks a catalogue against the DefaultRegistry.
+func Validate(cat *Hypercat) error {
+	return DefaultRegistry.Validate(cat)
+}
+
+// Register adds a rel definition to the registry. Returns ErrDuplicateRel if
+// the rel is already defined.
+func (r *Registry) Register(def RelDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.defs[def.Rel]; ok {
+		return ErrDuplicateRel
+	}
+
+	r.defs[def.Rel] = def
+
+	return nil
+}
+
+// Lookup returns the definition of the given rel, and whether it is defined.
+func (r *Registry) Lookup(rel string) (RelDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[rel]
+
+	return def, ok
+}
+
+// Definitions returns all definitions within the registry, sorted by rel.
+func (r *Registry) Definitions() []RelDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]RelDefinition, 0, len(r.defs))
+
+	for _, def := range r.defs {
+		defs = append(defs, def)
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Rel < defs[j].Rel
+	})
+
+	return defs
+}
+
+// Validate checks the metadata of a catalogue and of each of its items against
+// the registry, returning ValidationErrors describing every violation found,
+// or nil if there are none. Rels without a definition are not checked, nor
+// are rels whose definition has a Scope excluding the metadata.
+func (r *Registry) Validate(cat *Hypercat) error {
+	errs := r.validate("", cat.allMetadata(), CatalogueScope)
+
+	for i := range cat.Items {
+		errs = append(errs, r.validate(cat.Items[i].Href, cat.Items[i].allMetadata(), ItemScope)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValidateItem checks the metadata of an item against the registry. See
+// Validate for details.
+func (r *Registry) ValidateItem(item *Item) error {
+	errs := r.validate(item.Href, item.allMetadata(), ItemScope)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validate checks some metadata within the given scope against the registry.
+func (r *Registry) validate(href string, metadata Metadata, scope Scope) ValidationErrors {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errs := ValidationErrors{}
+	counts := map[string]int{}
+
+	for _, rel := range metadata {
+		for _, def := range r.matching(rel.Rel) {
+			if !def.Scope.includes(scope) {
+				continue
+			}
+
+			counts[def.Rel]++
+
+			err := def.CheckValue(rel.Val)
+			if err != nil {
+				errs = append(errs, &ValidationError{Href: href, Err: err})
+			}
+		}
+	}
+
+	rels := make([]string, 0, len(r.defs))
+	for rel := range r.defs {
+		rels = append(rels, rel)
+	}
+
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		def := r.defs[rel]
+		count := counts[rel]
+
+		if !def.Scope.includes(scope) {
+			continue
+		}
+
+		if count < def.Min {
+			errs = append(errs, &ValidationError{Href: href, Err: &RelError{Rel: rel, Err: ErrTooFewValues}})
+		}
+
+		if def.Max > 0 && count > def.Max {
+			errs = append(errs, &ValidationError{Href: href, Err: &RelError{Rel: rel, Err: ErrTooManyValues}})
+		}
+	}
+
+	return errs
+}
+
+// matching returns the definitions applying to the given rel: its own
+// definition, followed by any prefix definitions it starts with. It must be
+// called with the read lock held.
+func (r *Registry) matching(rel string) []RelDefinition {
+	var defs []RelDefinition
+
+	if def, ok := r.defs[rel]; ok {
+		defs = append(defs, def)
+	}
+
+	for _, def := range r.defs {
+		if def.Prefix && def.Rel != rel && strings.HasPrefix(rel, def.Rel) {
+			defs = append(defs, def)
+		}
+	}
+
+	return defs
+}
+
+// checkRegistered returns an error if the given rel is defined within the
+// DefaultRegistry with a different value type, or if the value isn't one of
+// its allowed values.
+func checkRegistered(rel, val string, t ValueType) error {
+	def, ok := DefaultRegistry.Lookup(rel)
+	if !ok {
+		return nil
+	}
+
+	if def.Type != t {
+		return &RelError{Rel: rel, Val: val, Err: fmt.Errorf("%w: defined as %v, not %v", ErrTypeMismatch, def.Type, t)}
+	}
+
+	if len(def.Allowed) > 0 {
+		return def.CheckValue(val)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package hypercat
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry()
+	def := RelDefinition{Rel: "urn:X-thingful:rels:sensorType", Label: "Sensor type", Allowed: []string{"temperature", "humidity"}}
+
+	err := registry.Register(def)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = registry.Register(def)
+	if err != ErrDuplicateRel {
+		t.Errorf("Register error, expected '%v', got '%v'", ErrDuplicateRel, err)
+	}
+
+	got, ok := registry.Lookup(def.Rel)
+	if !ok || got.Label != "Sensor type" {
+		t.Errorf("Lookup error, got '%v'", got)
+	}
+
+	_, ok = registry.Lookup("missing")
+	if ok {
+		t.Errorf("Lookup should not find an undefined rel")
+	}
+
+	if len(registry.Definitions()) != 1 {
+		t.Errorf("Definitions error, expected 1 definition, got '%v'", len(registry.Definitions()))
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	for _, rel := range []string{DescriptionRel, ContentTypeRel, SupportsSearchRel, LatitudeRel, LongitudeRel, LastUpdatedRel} {
+		if _, ok := DefaultRegistry.Lookup(rel); !ok {
+			t.Errorf("DefaultRegistry should define '%v'", rel)
+		}
+	}
+
+	err := Validate(testCatalogue(2))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	item := NewItem("/de", "")
+	item.SetDescriptionIn("de", "Beschreibung")
+
+	err = DefaultRegistry.ValidateItem(item)
+	if err != nil {
+		t.Errorf("A description in any language should satisfy the registry, got '%v'", err)
+	}
+
+	item.SetDescriptionIn("de", "")
+
+	err = DefaultRegistry.ValidateItem(item)
+
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || !errors.Is(errs[0], ErrTooFewValues) {
+		t.Errorf("ValidateItem error, expected '%v', got '%v'", ErrTooFewValues, err)
+	}
+}
+
+func TestRelDefinitionCheckValue(t *testing.T) {
+	var testcases = []struct {
+		def      RelDefinition
+		val      string
+		expected bool
+	}{
+		{RelDefinition{Rel: "rel", Type: StringValue}, "anything", true},
+		{RelDefinition{Rel: "rel", Type: FloatValue}, "1.5", true},
+		{RelDefinition{Rel: "rel", Type: FloatValue}, "abc", false},
+		{RelDefinition{Rel: "rel", Type: IntValue}, "1.5", false},
+		{RelDefinition{Rel: "rel", Type: BoolValue}, "true", true},
+		{RelDefinition{Rel: "rel", Type: TimeValue}, "2016-03-01", false},
+		{RelDefinition{Rel: "rel", Type: URLValue}, "http://example.com", true},
+		{RelDefinition{Rel: "rel", Type: URLValue}, "%zz", false},
+		{RelDefinition{Rel: "rel", Allowed: []string{"a", "b"}}, "b", true},
+		{RelDefinition{Rel: "rel", Allowed: []string{"a", "b"}}, "c", false},
+	}
+
+	for _, testcase := range testcases {
+		err := testcase.def.CheckValue(testcase.val)
+
+		if (err == nil) != testcase.expected {
+			t.Errorf("CheckValue error for '%v' with type '%v', got '%v'", testcase.val, testcase.def.Type, err)
+		}
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(RelDefinition{Rel: DescriptionRel, Min: 1, Max: 1})
+	registry.Register(RelDefinition{Rel: "sensorType", Min: 1, Allowed: []string{"temperature"}})
+	registry.Register(RelDefinition{Rel: LatitudeRel, Type: FloatValue, Max: 1})
+
+	cat := NewHypercat("Catalogue description")
+	cat.AddRel("sensorType", "temperature")
+
+	item1 := NewItem("/1", "Item 1")
+	item1.AddRel("sensorType", "temperature")
+	item1.AddRel(LatitudeRel, "51.5")
+	cat.AddItem(item1)
+
+	err := registry.Validate(cat)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	item2 := NewItem("/2", "")
+	item2.AddRel(LatitudeRel, "north")
+	item2.AddRel(LatitudeRel, "51.5")
+	cat.AddItem(item2)
+
+	err = registry.Validate(cat)
+
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Validate error, expected ValidationErrors, got '%v'", err)
+	}
+
+	expected := []error{strconv.ErrSyntax, ErrTooManyValues, ErrTooFewValues, ErrTooFewValues}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("Validate error, expected %v errors, got '%v'", len(expected), err)
+	}
+
+	for i, e := range errs {
+		if e.Href != "/2" {
+			t.Errorf("Validate error, expected href '/2', got '%v'", e.Href)
+		}
+
+		if !errors.Is(e, expected[i]) {
+			t.Errorf("Validate error, expected '%v', got '%v'", expected[i], e)
+		}
+	}
+
+	err = registry.ValidateItem(item1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRegistryValidateScope(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(RelDefinition{Rel: "sensorType", Scope: ItemScope, Min: 1})
+	registry.Register(RelDefinition{Rel: "publisher", Scope: CatalogueScope, Min: 1, Max: 1})
+
+	cat := NewHypercat("Catalogue description")
+	cat.AddRel("publisher", "Thingful")
+
+	item := NewItem("/1", "Item 1")
+	item.AddRel("sensorType", "temperature")
+	item.AddRel("publisher", "Thingful")
+	item.AddRel("publisher", "Partner")
+	cat.AddItem(item)
+
+	err := registry.Validate(cat)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	cat.RemoveRel("publisher")
+	cat.Items[0].RemoveRel("sensorType")
+
+	errs, ok := registry.Validate(cat).(ValidationErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Validate error, expected 2 errors, got '%v'", errs)
+	}
+
+	var testcases = []struct {
+		href string
+		rel  string
+	}{
+		{"", "publisher"},
+		{"/1", "sensorType"},
+	}
+
+	for i, testcase := range testcases {
+		var relErr *RelError
+
+		if errs[i].Href != testcase.href || !errors.As(errs[i], &relErr) || relErr.Rel != testcase.rel || !errors.Is(relErr, ErrTooFewValues) {
+			t.Errorf("Validate scope error, expected '%v' missing from '%v', got '%v'", testcase.rel, testcase.href, errs[i])
+		}
+	}
+}
+
+func TestTypedAccessorsConsultRegistry(t *testing.T) {
+	item := NewItem("/foo", "description")
+	item.AddRel(LatitudeRel, "1")
+	item.AddRel(SupportsSearchRel, "urn:X-hypercat:search:unknown")
+
+	_, err := item.Int(LatitudeRel)
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Typed accessor error, expected '%v', got '%v'", ErrTypeMismatch, err)
+	}
+
+	_, err = item.Float(LatitudeRel)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	registry := DefaultRegistry
+	DefaultRegistry = NewRegistry()
+	defer func() { DefaultRegistry = registry }()
+
+	DefaultRegistry.Register(RelDefinition{Rel: "level", Type: IntValue, Allowed: []string{"1", "2"}})
+	item.AddRel("level", "3")
+
+	_, err = item.Int("level")
+	if !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("Typed accessor error, expected '%v', got '%v'", ErrValueNotAllowed, err)
+	}
+}